@@ -0,0 +1,74 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/resolvers"
+)
+
+// Documents returns the GraphQL operation documents for the operations of a
+// resolver, keyed by file name: a <Model>Fields fragment and one document
+// per query, mutation and subscription.
+func Documents(r resolvers.Resolver) map[string]string {
+	fragment := FragmentName(r.Model)
+	docs := map[string]string{
+		fragment + ".graphql": Fragment(r.Model),
+	}
+	for _, sig := range r.Signatures() {
+		doc := Operation(sig, r.Model)
+		if selection(sig.Returns, r.Model) != "" {
+			doc = "#import \"./" + fragment + ".graphql\"\n\n" + doc
+		}
+		docs[sig.Name+".graphql"] = doc
+	}
+	return docs
+}
+
+func FragmentName(m prismaUtil.Model) string {
+	return m.Name + "Fields"
+}
+
+// Fragment selects every field of the model's object type.
+func Fragment(m prismaUtil.Model) string {
+	doc := "fragment " + FragmentName(m) + " on " + m.Name + " {\n"
+	for _, f := range m.Fields {
+		if (f.Typename == prismaUtil.NPType && !f.Enum) || !f.InMode(prismaUtil.ObjectMode) {
+			continue
+		}
+		doc += "  " + f.Name + "\n"
+	}
+	return doc + "}\n"
+}
+
+// Operation returns the document of an operation, with a variable for each
+// argument and the model's fragment as the selection of returned records.
+func Operation(sig resolvers.Signature, m prismaUtil.Model) string {
+	variables := []string{}
+	args := []string{}
+	for _, arg := range sig.Args {
+		variables = append(variables, "$"+arg.Name+": "+arg.Type)
+		args = append(args, arg.Name+": $"+arg.Name)
+	}
+	doc := strings.ToLower(sig.Kind) + " " + sig.Name
+	field := sig.Name
+	if len(args) > 0 {
+		doc += "(" + strings.Join(variables, ", ") + ")"
+		field += "(" + strings.Join(args, ", ") + ")"
+	}
+	doc += " {\n  " + field + selection(sig.Returns, m) + "\n}\n"
+	return doc
+}
+
+// selection returns the selection set of a returned type, empty for scalars.
+func selection(returns string, m prismaUtil.Model) string {
+	fragment := "..." + FragmentName(m)
+	switch strings.TrimSuffix(returns, "!") {
+	case m.Name:
+		return " {\n    " + fragment + "\n  }"
+	case m.Name + "Connection":
+		return " {\n    edges {\n      cursor\n      node {\n        " + fragment + "\n      }\n    }\n" +
+			"    pageInfo {\n      hasNextPage\n      hasPreviousPage\n      startCursor\n      endCursor\n    }\n  }"
+	}
+	return ""
+}
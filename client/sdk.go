@@ -0,0 +1,223 @@
+package client
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/resolvers"
+)
+
+// CLIENT_TS maps Prisma types to the TypeScript types of their JSON encoding,
+// which is what a client receives and sends.
+var CLIENT_TS = map[prismaUtil.PrismaType]string{
+	prismaUtil.FloatType:    "number",
+	prismaUtil.IntType:      "number",
+	prismaUtil.BytesType:    "string",
+	prismaUtil.JsonType:     "unknown",
+	prismaUtil.BooleanType:  "boolean",
+	prismaUtil.StringType:   "string",
+	prismaUtil.BigIntType:   "string",
+	prismaUtil.DateTimeType: "string",
+}
+
+// SDK returns a TypeScript client for the resolvers: interfaces mirroring
+// the generated types and a createClient function with a typed method per
+// query and mutation. Enum types are imported from the enums module, the
+// path of the resolvers' enums.ts relative to the client.
+func SDK(schema prismaUtil.Schema, resolverList []resolvers.Resolver, enums string) string {
+	interfaces := []string{}
+	fragments := []string{}
+	methods := []string{}
+	enumNames := map[string]struct{}{}
+	relay := false
+	for _, r := range resolverList {
+		relay = relay || r.Relay
+		for _, name := range r.Model.EnumNames() {
+			enumNames[name] = struct{}{}
+		}
+		interfaces = append(interfaces, modelInterfaces(schema, r)...)
+		fragments = append(fragments, "const "+FragmentName(r.Model)+" = `\n"+Fragment(r.Model)+"`;")
+		for _, sig := range r.Signatures() {
+			if sig.Kind != "Subscription" {
+				methods = append(methods, method(sig, r.Model))
+			}
+		}
+	}
+	if relay {
+		interfaces = append([]string{"export interface PageInfo {\n\thasNextPage: boolean;\n\thasPreviousPage: boolean;\n\tstartCursor?: string | null;\n\tendCursor?: string | null;\n}"}, interfaces...)
+	}
+
+	header := "// Generated by genql client. Do not edit.\n\n"
+	if len(enumNames) > 0 {
+		header += enumImport(enumNames, enums) + "\n"
+	}
+	header += "export type Fetch = (url: string, init: { method: string; headers: Record<string, string>; body: string }) => Promise<{ json(): Promise<any> }>;\n\n" +
+		"export class GraphQLRequestError extends Error {\n" +
+		"\tconstructor(public errors: { message: string; extensions?: Record<string, unknown> }[]) {\n" +
+		"\t\tsuper(errors.map((error) => error.message).join(\"\\n\"));\n\t}\n}"
+	client := "export const createClient = (fetch: Fetch, url: string, headers: Record<string, string> = {}) => {\n" +
+		"\tconst request = async <T>(query: string, variables: Record<string, unknown>): Promise<T> => {\n" +
+		"\t\tconst response = await fetch(url, {\n\t\t\tmethod: \"POST\",\n\t\t\theaders: { \"Content-Type\": \"application/json\", ...headers },\n" +
+		"\t\t\tbody: JSON.stringify({ query, variables }),\n\t\t});\n" +
+		"\t\tconst { data, errors } = await response.json();\n" +
+		"\t\tif (errors?.length) {\n\t\t\tthrow new GraphQLRequestError(errors);\n\t\t}\n\t\treturn data;\n\t};\n\n" +
+		"\treturn {\n" + strings.Join(methods, "\n") + "\n\t};\n};"
+	return header + "\n\n" + strings.Join(interfaces, "\n\n") + "\n\n" + strings.Join(fragments, "\n\n") + "\n\n" + client + "\n"
+}
+
+// modelInterfaces mirrors the classes the resolvers command writes to the
+// model's types.ts.
+func modelInterfaces(schema prismaUtil.Schema, r resolvers.Resolver) []string {
+	m := r.Model
+	object := m
+	if r.Relay {
+		object = m.WithGlobalId()
+	}
+	relations := schema.Relations(m)
+	interfaces := []string{tsInterface(m.Name, object.FieldsIn(prismaUtil.ObjectMode), prismaUtil.ObjectMode)}
+	if r.Relay {
+		interfaces = append(interfaces, "export interface "+m.Name+"Connection {\n\tedges: { cursor: string; node: "+m.Name+" }[];\n\tpageInfo: PageInfo;\n}")
+	}
+	for _, rel := range relations {
+		unique := rel.UniqueModel()
+		interfaces = append(interfaces, tsInterface(unique.Name, unique.FieldsIn(prismaUtil.UpdateMode), prismaUtil.UpdateMode))
+		if create := rel.CreateModel(); rel.Creatable() {
+			interfaces = append(interfaces, tsInterface(create.Name, create.FieldsIn(prismaUtil.CreateMode), prismaUtil.CreateMode))
+		}
+		interfaces = append(interfaces, relationInterface(rel.CreateNestedInput()), relationInterface(rel.UpdateNestedInput()))
+	}
+	create := tsInterface("create"+m.Name+"Input", m.FieldsIn(prismaUtil.CreateMode, relations...), prismaUtil.CreateMode)
+	update := tsInterface("update"+m.Name+"Input", object.FieldsIn(prismaUtil.UpdateMode), prismaUtil.UpdateMode)
+	interfaces = append(interfaces, withRelations(create, relations, prismaUtil.CreateMode), withRelations(update, relations, prismaUtil.UpdateMode))
+	if r.HasUpdateManyInput() {
+		interfaces = append(interfaces, tsInterface("updateMany"+m.Name+"Input", m.FieldsIn(prismaUtil.UpdateManyMode), prismaUtil.UpdateManyMode))
+	}
+	if r.HasWhereInput() {
+		interfaces = append(interfaces, tsInterface(m.Name+"WhereInput", m.FieldsIn(prismaUtil.WhereMode), prismaUtil.WhereMode))
+	}
+	return interfaces
+}
+
+func tsInterface(name string, fields []prismaUtil.Field, mode prismaUtil.TypeMode) string {
+	ts := "export interface " + name + " {\n"
+	for _, f := range fields {
+		tsType := CLIENT_TS[f.Typename]
+		if f.Enum {
+			tsType = f.NPType
+		}
+		if f.IsArray {
+			tsType += "[]"
+		}
+		if f.Nullable(mode) {
+			ts += "\t" + f.Name + "?: " + tsType + " | null;\n"
+		} else {
+			ts += "\t" + f.Name + ": " + tsType + ";\n"
+		}
+	}
+	return ts + "}"
+}
+
+func withRelations(ts string, relations []prismaUtil.Relation, mode prismaUtil.TypeMode) string {
+	fields := ""
+	for _, rel := range relations {
+		fields += "\t" + rel.Field.Name + "?: " + rel.NestedInputName(mode) + ";\n"
+	}
+	return strings.TrimSuffix(ts, "}") + fields + "}"
+}
+
+func relationInterface(in prismaUtil.RelationInput) string {
+	ts := "export interface " + in.Name + " {\n"
+	for _, f := range in.Fields {
+		tsType := f.Type
+		if f.Type == "Boolean" {
+			tsType = "boolean"
+		}
+		if f.IsList {
+			tsType += "[]"
+		}
+		ts += "\t" + f.Name + "?: " + tsType + ";\n"
+	}
+	return ts + "}"
+}
+
+// method returns the client method of an operation, taking its arguments in
+// order and resolving to the operation's field of the response.
+func method(sig resolvers.Signature, m prismaUtil.Model) string {
+	params := []string{}
+	variables := []string{}
+	for _, arg := range sig.Args {
+		if strings.HasSuffix(arg.Type, "!") {
+			params = append(params, arg.Name+": "+tsType(arg.Type))
+		} else {
+			params = append(params, arg.Name+"?: "+tsType(arg.Type))
+		}
+		variables = append(variables, arg.Name)
+	}
+	returns := tsType(sig.Returns)
+	query := Operation(sig, m)
+	if selection(sig.Returns, m) != "" {
+		query += "${" + FragmentName(m) + "}"
+	}
+	return "\t\t" + sig.Name + ": (" + strings.Join(params, ", ") + ") =>\n" +
+		"\t\t\trequest<{ " + sig.Name + ": " + returns + " }>(`\n" + indentLines(query, "\t\t\t\t") + "\t\t\t`, { " + strings.Join(variables, ", ") + " }).then((response) => response." + sig.Name + "),"
+}
+
+// tsType returns the TypeScript type of a GraphQL type reference, e.g.
+// "[createFriendInput!]!" is createFriendInput[].
+func tsType(graphql string) string {
+	if !strings.HasSuffix(graphql, "!") {
+		return tsType(graphql+"!") + " | null"
+	}
+	graphql = strings.TrimSuffix(graphql, "!")
+	if strings.HasPrefix(graphql, "[") {
+		return tsType(strings.TrimSuffix(strings.TrimPrefix(graphql, "["), "]")) + "[]"
+	}
+	switch graphql {
+	case "String", "ID":
+		return "string"
+	case "Int", "Float":
+		return "number"
+	case "Boolean":
+		return "boolean"
+	}
+	for t, name := range prismaUtil.MAPPED_GRAPHQL {
+		if name == graphql {
+			return CLIENT_TS[t]
+		}
+	}
+	return graphql
+}
+
+func indentLines(text string, prefix string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Resolvers returns the resolvers of every model with an id, sorted by model
+// name, generated with the given operations and roles.
+func Resolvers(schema prismaUtil.Schema, functions []string, roles map[string][]string, relay bool) []resolvers.Resolver {
+	models := append([]prismaUtil.Model{}, schema.Models...)
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	list := []resolvers.Resolver{}
+	for _, m := range models {
+		if _, ok := m.IdField(); ok {
+			list = append(list, resolvers.Resolver{Model: schema.WithEnums(m), Functions: functions, Roles: roles, Relay: relay})
+		}
+	}
+	return list
+}
+
+// enumImport returns a type-only import of the enums, which keeps the client
+// free of runtime dependencies.
+func enumImport(names map[string]struct{}, module string) string {
+	sorted := []string{}
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return "import type { " + strings.Join(sorted, ", ") + " } from \"" + module + "\";\n"
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tk04/genql/client"
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/resolvers"
+)
+
+var clientCmd = &cobra.Command{
+	Use:   "client",
+	Short: "Generate a typed TypeScript client for the GraphQL resolvers",
+	Long:  "Generate a TypeScript client with a typed method for every query and mutation the resolvers command generates, for all models or the ones given.\n\n Usage: genql client [model names] [--output client.ts] [--Except op | --Only op].",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		relay, _ := cmd.Flags().GetBool("relay")
+		except, _ := cmd.Flags().GetStringArray("Except")
+		only, _ := cmd.Flags().GetStringArray("Only")
+		values, _ := cmd.Flags().GetStringSlice("roles")
+		roles := resolvers.ParseRoles(values)
+		include, err := resolvers.SelectOperations(except, only, roles)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		schema := prismaUtil.LoadSchema()
+		selected := map[string]struct{}{}
+		for _, name := range args {
+			if !schema.IsModel(name) {
+				fmt.Printf("Model (%s) not found in prisma.schema\n", name)
+				os.Exit(1)
+			}
+			selected[name] = struct{}{}
+		}
+		list := []resolvers.Resolver{}
+		for _, r := range client.Resolvers(schema, include, roles, relay) {
+			if _, ok := selected[r.Model.Name]; ok || len(args) == 0 {
+				list = append(list, r)
+			}
+		}
+		enums, err := enumsModule(output)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(output, []byte(client.SDK(schema, list, enums)), 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+// enumsModule returns the import path of the enums.ts the resolvers share,
+// relative to the client at output.
+func enumsModule(output string) (string, error) {
+	dir, err := filepath.Abs(filepath.Dir(output))
+	if err != nil {
+		return "", err
+	}
+	enums, err := filepath.Abs("./src/resolvers/enums")
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(dir, enums)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel, nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/resolvers"
+)
+
+var modelName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+var destroyCmd = &cobra.Command{
+	Use:   "destroy",
+	Short: "Remove the GraphQL resolvers generated for a Prisma Model",
+	Long:  "Remove the resolvers generated for a Prisma Model, and remove them from src/resolvers/index.ts. The model stays in schema.prisma.\n\n Usage: genql destroy [model name].",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		schema := prismaUtil.LoadSchema()
+		root, err := filepath.Abs("./src/resolvers")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		paths := []string{}
+		for _, name := range args {
+			if !modelName.MatchString(name) || !schema.IsModel(name) {
+				fmt.Printf("model (%s) not found in schema.prisma\n", name)
+				os.Exit(1)
+			}
+			resolverPath := filepath.Join(root, name)
+			if rel, err := filepath.Rel(root, resolverPath); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+				fmt.Printf("resolvers for model (%s) are not in src/resolvers\n", name)
+				os.Exit(1)
+			}
+			if _, err := os.Stat(resolverPath); err != nil {
+				fmt.Printf("resolvers for model (%s) not found in ./src/resolvers/%s\n", name, name)
+				os.Exit(1)
+			}
+			paths = append(paths, resolverPath)
+		}
+		for i, name := range args {
+			if err := os.RemoveAll(paths[i]); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			removed := []string{name + "Resolver"}
+			lastNode, err := resolvers.RemoveNodeLoader(name)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if lastNode {
+				removed = append(removed, "NodeResolver")
+			}
+			emptied, err := resolvers.RemoveFromIndex(removed...)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if emptied {
+				fmt.Println("no resolvers are left, removed src/resolvers/index.ts")
+			}
+		}
+	},
+}
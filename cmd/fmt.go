@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tk04/genql/prismaUtil"
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt",
+	Short: "Format the Prisma schema",
+	Long:  "Format schema.prisma without needing Node: aligns fields into columns, normalises attribute spacing and sorts block attributes.\n\n Usage: genql fmt [--check].",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		check, _ := cmd.Flags().GetBool("check")
+		if !check {
+			prismaUtil.FormatSchemaFile()
+			return
+		}
+
+		f, err := os.ReadFile(prismaUtil.GetSchemaPath())
+		if err != nil {
+			fmt.Printf("schema.prisma does not exist in %s\n", prismaUtil.GetSchemaPath())
+			os.Exit(1)
+		}
+		if string(prismaUtil.Format(f)) != string(f) {
+			fmt.Printf("%s is not formatted, run genql fmt\n", prismaUtil.SchemaFile)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tk04/genql/scaffold"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a GraphQL server project",
+	Long:  "Create a Type-GraphQL server project using Prisma, ready for the model and resolvers commands.\n\n Usage: genql init [directory] [--provider postgresql] [--server apollo].",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		provider, _ := cmd.Flags().GetString("provider")
+		server, _ := cmd.Flags().GetString("server")
+
+		if _, ok := scaffold.DatabaseURLs[provider]; !ok {
+			providers := []string{}
+			for name := range scaffold.DatabaseURLs {
+				providers = append(providers, name)
+			}
+			sort.Strings(providers)
+			fmt.Printf("unknown provider (%s), use one of %s\n", provider, strings.Join(providers, ", "))
+			os.Exit(1)
+		}
+		if server != "apollo" && server != "yoga" {
+			fmt.Printf("unknown server (%s), use apollo or yoga\n", server)
+			os.Exit(1)
+		}
+		dir := args[0]
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		project := scaffold.Project{Name: filepath.Base(abs), Provider: provider, Server: server}
+		if err := project.Create(dir); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Created %s. Next:\n  cd %s\n  npm install\n  genql model User id:id:ai name:string\n  genql resolvers User\n", dir, dir)
+	},
+}
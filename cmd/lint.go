@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tk04/genql/config"
+	"github.com/tk04/genql/lint"
+	"github.com/tk04/genql/prismaUtil"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the Prisma schema for common modelling mistakes",
+	Long:  "Check schema.prisma for common modelling mistakes and report them with their file:line position.\n\n Usage: genql lint [--json] [--disable rule] [--enable rule].\n Rules can also be toggled in genql.json under \"lint\": {\"rules\": {\"fk-index\": false}}.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		disabled, _ := cmd.Flags().GetStringArray("disable")
+		enabled, _ := cmd.Flags().GetStringArray("enable")
+
+		rules := config.Load().Lint.Rules
+		if rules == nil {
+			rules = map[string]bool{}
+		}
+		for _, name := range disabled {
+			rules[name] = false
+		}
+		for _, name := range enabled {
+			rules[name] = true
+		}
+		for name := range rules {
+			if _, ok := lint.FindRule(name); !ok {
+				fmt.Printf("unknown lint rule (%s)\n", name)
+				os.Exit(1)
+			}
+		}
+
+		schema := prismaUtil.LoadSchema()
+		issues := lint.Run(schema, prismaUtil.SchemaFile, func(rule string) bool {
+			enabled, ok := rules[rule]
+			return !ok || enabled
+		})
+
+		if asJSON {
+			out, _ := json.MarshalIndent(issues, "", "  ")
+			fmt.Println(string(out))
+		} else {
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+		}
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+	},
+}
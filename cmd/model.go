@@ -4,6 +4,7 @@ import (
 	"fmt"
 	pluralize "github.com/gertd/go-pluralize"
 	"github.com/spf13/cobra"
+	"github.com/tk04/genql/config"
 	"github.com/tk04/genql/prismaUtil"
 	"os"
 	"strings"
@@ -35,13 +36,25 @@ var modelCmd = &cobra.Command{
 		for _, rel := range relations {
 			prismaModel.AddField(rel)
 		}
+		timestamps := config.Load().Model.Timestamps
+		if cmd.Flags().Changed("timestamps") {
+			timestamps, _ = cmd.Flags().GetBool("timestamps")
+		}
+		if timestamps {
+			prismaModel.AddTimestamps()
+		}
+		if softDelete, _ := cmd.Flags().GetBool("soft-delete"); softDelete {
+			prismaModel.AddSoftDelete()
+		}
 
 		f, err := os.OpenFile(prismaUtil.GetSchemaPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		defer f.Close()
-		f.WriteString(prismaModel.String())
 		if err != nil {
 			fmt.Println(err)
+			os.Exit(1)
 		}
+		f.WriteString("\n" + prismaModel.String() + "\n")
+		f.Close()
+		prismaUtil.FormatSchemaFile()
 	},
 }
 
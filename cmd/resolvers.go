@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/tk04/genql/client"
 	"github.com/tk04/genql/prismaUtil"
 	"github.com/tk04/genql/resolvers"
 	"os"
+	"path"
+	"path/filepath"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 )
@@ -12,35 +16,191 @@ import (
 var resolversCmd = &cobra.Command{
 	Use:   "resolvers",
 	Short: "Generate GraphQL resolvers for a Prisma Model",
-	Long:  "Generate CRUD GraphQL resolvers for a Prisma Model.\n\n Usage: genql resolvers [model name].",
-	Args:  cobra.MinimumNArgs(1),
+	Long:  "Generate CRUD GraphQL resolvers for a Prisma Model, or for every model with --all.\n\n Usage: genql resolvers [model name] | --all [--exclude pattern].",
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		arg, _ := cmd.Flags().GetStringArray("Except")
+		except, _ := cmd.Flags().GetStringArray("Except")
+		only, _ := cmd.Flags().GetStringArray("Only")
+		roles, _ := cmd.Flags().GetStringSlice("roles")
+		operationRoles := resolvers.ParseRoles(roles)
+		include, err := resolvers.SelectOperations(except, only, operationRoles)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		auth, _ := cmd.Flags().GetBool("auth")
+		owner, _ := cmd.Flags().GetString("owner")
+		mapErrors, _ := cmd.Flags().GetBool("errors")
+		subscriptions, _ := cmd.Flags().GetBool("subscriptions")
+		relay, _ := cmd.Flags().GetBool("relay")
 
-		val := struct{}{}
-		funcs := map[string]struct{}{
-			"get":    val,
-			"create": val,
-			"update": val,
-			"delete": val,
+		target, _ := cmd.Flags().GetString("target")
+		if target != "type-graphql" && target != "nestjs" {
+			fmt.Printf("unknown target (%s), use type-graphql or nestjs\n", target)
+			os.Exit(1)
 		}
-		for _, val := range arg {
-			delete(funcs, val)
+		if target == "nestjs" && (auth || owner != "" || len(roles) > 0 || subscriptions || relay) {
+			fmt.Println("--auth, --owner, --roles, --subscriptions and --relay are not supported with --target nestjs")
+			os.Exit(1)
 		}
-		include := []string{}
-		for k := range funcs {
-			include = append(include, k)
+		all, _ := cmd.Flags().GetBool("all")
+		exclude, _ := cmd.Flags().GetStringSlice("exclude")
+		for _, pattern := range exclude {
+			if _, err := path.Match(pattern, ""); err != nil {
+				fmt.Printf("invalid exclude pattern (%s)\n", pattern)
+				os.Exit(1)
+			}
 		}
-
-		resolverPath := "./src/resolvers/" + args[0]
-		err := os.MkdirAll(resolverPath, os.ModePerm)
-		if err != nil {
-			fmt.Println("err")
+		if all == (len(args) > 0) {
+			fmt.Println("pass either a model name or --all")
+			os.Exit(1)
+		}
+		if len(exclude) > 0 && !all {
+			fmt.Println("--exclude can only be used with --all")
 			os.Exit(1)
 		}
 
-		model := prismaUtil.GetModel(args[0])
-		resolver := resolvers.Resolver{Model: model, Functions: include}
-		resolver.CreateFiles()
+		schema := prismaUtil.LoadSchema()
+		newResolver := func(model prismaUtil.Model) resolvers.Resolver {
+			return resolvers.Resolver{
+				Model:         schema.WithEnums(model).WithOwner(owner),
+				Functions:     include,
+				Auth:          auth || owner != "" || len(roles) > 0,
+				Roles:         operationRoles,
+				OwnerField:    owner,
+				Relations:     schema.Relations(model),
+				Errors:        mapErrors,
+				Subscriptions: subscriptions,
+				Relay:         relay,
+			}
+		}
+		withZod, _ := cmd.Flags().GetBool("zod")
+		generate := func(resolver resolvers.Resolver) error {
+			if target == "nestjs" {
+				if err := resolver.CreateNestFiles(); err != nil {
+					return err
+				}
+			} else {
+				resolverPath := "./src/resolvers/" + resolver.Model.Name
+				if err := os.MkdirAll(resolverPath, os.ModePerm); err != nil {
+					return err
+				}
+				if err := resolver.CreateFiles(); err != nil {
+					return err
+				}
+			}
+			if withZod {
+				if err := writeZodSchemas(resolver.Model, schema); err != nil {
+					return err
+				}
+			}
+			if output, _ := cmd.Flags().GetString("client"); output != "" {
+				return writeDocuments(resolver, output)
+			}
+			return nil
+		}
+
+		failed := false
+		if !all {
+			model, ok := schema.Model(args[0])
+			if !ok {
+				fmt.Printf("Model (%s) not found in prisma.schema\n", args[0])
+				os.Exit(1)
+			}
+			if _, ok := model.Field(owner); owner != "" && !ok {
+				fmt.Printf("owner field (%s) not found in model %s\n", owner, model.Name)
+				os.Exit(1)
+			}
+			resolver := newResolver(model)
+			if err := resolver.Check(); err != nil {
+				fmt.Printf("can't generate resolvers for model %s: %s\n", model.Name, err)
+				os.Exit(1)
+			}
+			if err := generate(resolver); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		} else {
+			// checked first, so the model isn't left with resolvers but no schemas
+			zodPath := func(model prismaUtil.Model) (string, bool) {
+				filePath := schemasPath + model.Name + ".ts"
+				_, err := os.Stat(filePath)
+				return filePath, withZod && err == nil
+			}
+			failed = generateAll(schema, exclude, target, zodPath, newResolver, generate)
+		}
+		if target != "nestjs" {
+			if withSchema, _ := cmd.Flags().GetBool("schema"); withSchema || resolvers.SchemaFileExists() {
+				if err := resolvers.WriteSchemaFile(); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
 	},
 }
+
+// generateAll generates the resolvers of every model in the schema that
+// isn't excluded, and prints which models were created, skipped or failed.
+// Composite types and models whose resolvers already exist are skipped. It
+// reports whether any model failed.
+func generateAll(schema prismaUtil.Schema, exclude []string, target string, zodPath func(prismaUtil.Model) (string, bool), newResolver func(prismaUtil.Model) resolvers.Resolver, generate func(resolvers.Resolver) error) bool {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tSTATUS\tDETAIL")
+	counts := map[string]int{}
+	for _, model := range schema.Models {
+		resolver := newResolver(model)
+		dir := "src/resolvers/" + model.Name
+		if target == "nestjs" {
+			dir = "src/" + resolvers.NestDir(model.Name)
+		}
+		status, detail := "created", dir
+		if model.Composite() {
+			status, detail = "skipped", "composite type"
+		} else if pattern, ok := matchAny(exclude, model.Name); ok {
+			status, detail = "skipped", "excluded by "+pattern
+		} else if _, err := os.Stat(dir); err == nil {
+			status, detail = "skipped", dir+" already exists"
+		} else if err := resolver.Check(); err != nil {
+			status, detail = "failed", err.Error()
+		} else if filePath, exists := zodPath(model); exists {
+			status, detail = "failed", filePath+" already exists"
+		} else if err := generate(resolver); err != nil {
+			status, detail = "failed", err.Error()
+		}
+		counts[status]++
+		fmt.Fprintln(w, model.Name+"\t"+status+"\t"+detail)
+	}
+	w.Flush()
+	fmt.Printf("%d created, %d skipped, %d failed\n", counts["created"], counts["skipped"], counts["failed"])
+	return counts["failed"] > 0
+}
+
+// matchAny returns the first of the glob patterns matching name.
+func matchAny(patterns []string, name string) (string, bool) {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// writeDocuments writes the client operation documents of a resolver to
+// <output>/<Model>, replacing documents from earlier runs.
+func writeDocuments(resolver resolvers.Resolver, output string) error {
+	dir := filepath.Join(output, resolver.Model.Name)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	for name, doc := range client.Documents(resolver) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(doc), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
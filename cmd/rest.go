@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/rest"
+)
+
+var restCmd = &cobra.Command{
+	Use:   "rest",
+	Short: "Generate Express routes and an OpenAPI document for Prisma Models",
+	Long:  "Generate an Express router with GET, POST, PATCH and DELETE routes for each model given, and add the routes to an OpenAPI 3.1 document.\n\n Usage: genql rest [model names] [--openapi openapi.json].",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		openapi, _ := cmd.Flags().GetString("openapi")
+
+		schema := prismaUtil.LoadSchema()
+		doc := readOpenAPI(openapi)
+		models := []prismaUtil.Model{}
+		for _, name := range args {
+			model, ok := schema.Model(name)
+			if !ok {
+				fmt.Printf("Model (%s) not found in prisma.schema\n", name)
+				os.Exit(1)
+			}
+			if _, ok := model.IdField(); !ok {
+				fmt.Printf("Model (%s) has no id field\n", name)
+				os.Exit(1)
+			}
+			models = append(models, model)
+		}
+		failed := false
+		for _, model := range models {
+			written, err := writeRouter(model, schema)
+			if err != nil {
+				fmt.Println(err)
+				failed = true
+				continue
+			}
+			if !written {
+				fmt.Printf("file (%s%s.ts) already exists, skipped\n", routesPath, model.Name)
+			}
+			doc.AddModel(model, schema)
+		}
+		out, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(openapi, append(out, '\n'), 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+const routesPath = "./src/routes/"
+
+// writeRouter writes the Express router of a model to src/routes/<Model>.ts,
+// leaving an existing router untouched. It reports whether it wrote the file.
+func writeRouter(model prismaUtil.Model, schema prismaUtil.Schema) (bool, error) {
+	if err := os.MkdirAll(routesPath, os.ModePerm); err != nil {
+		return false, err
+	}
+	filePath := routesPath + model.Name + ".ts"
+	if _, err := os.Stat(filePath); err == nil {
+		return false, nil
+	}
+	if err := os.WriteFile(filePath, []byte(rest.Router(model, schema)), 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// readOpenAPI reads the OpenAPI document at path so routes of other models
+// are kept, or returns a new one if it doesn't exist.
+func readOpenAPI(path string) rest.Document {
+	f, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rest.NewDocument()
+	} else if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	doc := rest.Document{}
+	if err := json.Unmarshal(f, &doc); err != nil {
+		fmt.Printf("invalid OpenAPI document (%s): %s\n", path, err)
+		os.Exit(1)
+	}
+	return doc
+}
@@ -5,17 +5,31 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/tk04/genql/config"
+	"github.com/tk04/genql/prismaUtil"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "genql",
 	Short: "genql is a server side GraphQL & Prisma code generator",
 	Long:  "A code generator that reliably generates Prisma database schemas followed by CRUD GraphQL resolvers for each generated model.",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		prismaUtil.ConfigureScalars(config.Load().Scalars)
+	},
 }
 
 func Execute() {
 	rootCmd.AddCommand(modelCmd)
 	rootCmd.AddCommand(resolversCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(fmtCmd)
+	rootCmd.AddCommand(sdlCmd)
+	rootCmd.AddCommand(clientCmd)
+	rootCmd.AddCommand(zodCmd)
+	rootCmd.AddCommand(restCmd)
+	rootCmd.AddCommand(trpcCmd)
+	rootCmd.AddCommand(destroyCmd)
+	rootCmd.AddCommand(initCmd)
 
 	var OTMRelation string // one to many relationship
 	var OTORelation string // one to one relationship
@@ -23,9 +37,73 @@ func Execute() {
 	modelCmd.Flags().StringVarP(&OTMRelation, "OneToMany", "r", "", "Define a one-to-many relationship between two models")
 	modelCmd.Flags().StringVarP(&OTORelation, "OneToOne", "1", "", "Define a one-to-one relationship between two models")
 	modelCmd.Flags().StringVarP(&MTORelation, "ManyToMany", "m", "", "Define a many-to-one relationship between two models")
+	var Timestamps bool
+	modelCmd.Flags().BoolVar(&Timestamps, "timestamps", false, "Add createdAt and updatedAt fields (default from genql.json)")
+	var SoftDelete bool
+	modelCmd.Flags().BoolVar(&SoftDelete, "soft-delete", false, "Add a deletedAt field so generated resolvers soft-delete records")
 
 	var Exceptions []string
 	resolversCmd.Flags().StringArrayVarP(&Exceptions, "Except", "e", []string{}, "Define operations not to be included in a given resolver")
+	var Only []string
+	resolversCmd.Flags().StringArrayVarP(&Only, "Only", "o", []string{}, "Define the only operations to be included in a given resolver")
+
+	var Auth bool
+	var Roles []string
+	var Owner string
+	resolversCmd.Flags().BoolVar(&Auth, "auth", false, "Add @Authorized() to every operation and generate an authChecker")
+	resolversCmd.Flags().StringSliceVar(&Roles, "roles", []string{}, "Roles allowed per operation, e.g. create=ADMIN,delete=ADMIN")
+	resolversCmd.Flags().StringVar(&Owner, "owner", "", "Owner FK limiting operations to the authenticated user's records, e.g. --owner ownerId")
+	var Errors bool
+	resolversCmd.Flags().BoolVar(&Errors, "errors", true, "Map Prisma errors to GraphQL errors, use --errors=false to disable")
+	var Client string
+	resolversCmd.Flags().StringVar(&Client, "client", "", "Write client operation documents for the resolvers to a directory, e.g. --client src/graphql")
+	var Zod bool
+	resolversCmd.Flags().BoolVar(&Zod, "zod", false, "Also generate Zod schemas for the model's inputs")
+	var Subscriptions bool
+	var Relay bool
+	resolversCmd.Flags().BoolVar(&Relay, "relay", false, "Generate Relay global ids, a Node interface and a connection query")
+	resolversCmd.Flags().BoolVar(&Subscriptions, "subscriptions", false, "Publish create, update and delete events and generate subscriptions for them")
+	var All bool
+	var Exclude []string
+	resolversCmd.Flags().BoolVar(&All, "all", false, "Generate resolvers for every model in schema.prisma")
+	resolversCmd.Flags().StringSliceVar(&Exclude, "exclude", []string{}, "Models not to generate with --all, as glob patterns, e.g. Audit*")
+	var Schema bool
+	resolversCmd.Flags().BoolVar(&Schema, "schema", false, "Write src/resolvers/schema.ts building the schema of every generated resolver")
+	var Target string
+	resolversCmd.Flags().StringVar(&Target, "target", "type-graphql", "Framework to generate the resolvers for, type-graphql or nestjs")
+
+	var LintJSON bool
+	var LintDisabled, LintEnabled []string
+	lintCmd.Flags().BoolVar(&LintJSON, "json", false, "Print issues as JSON")
+	lintCmd.Flags().StringArrayVar(&LintDisabled, "disable", []string{}, "Disable a lint rule")
+	lintCmd.Flags().StringArrayVar(&LintEnabled, "enable", []string{}, "Enable a lint rule disabled in genql.json")
+
+	var FmtCheck bool
+	fmtCmd.Flags().BoolVar(&FmtCheck, "check", false, "Exit with an error if the schema is not formatted, without changing it")
+
+	var SDLOutput string
+	sdlCmd.Flags().StringVarP(&SDLOutput, "output", "o", "schema.graphql", "Path of the generated schema file")
+	var SDLExceptions, SDLOnly, SDLRoles []string
+	sdlCmd.Flags().StringArrayVarP(&SDLExceptions, "Except", "e", []string{}, "Operations the resolvers were generated without")
+	sdlCmd.Flags().StringArrayVar(&SDLOnly, "Only", []string{}, "The only operations the resolvers were generated with")
+	sdlCmd.Flags().StringSliceVar(&SDLRoles, "roles", []string{}, "Roles the resolvers were generated with, adds includeDeleted queries with --roles includeDeleted=ROLE")
+
+	var ClientOutput string
+	var ClientRelay bool
+	clientCmd.Flags().StringVarP(&ClientOutput, "output", "o", "client.ts", "Path of the generated client")
+	clientCmd.Flags().BoolVar(&ClientRelay, "relay", false, "Generate the client for resolvers generated with --relay")
+	var ClientExceptions, ClientOnly []string
+	clientCmd.Flags().StringArrayVarP(&ClientExceptions, "Except", "e", []string{}, "Operations the resolvers were generated without")
+	clientCmd.Flags().StringArrayVar(&ClientOnly, "Only", []string{}, "The only operations the resolvers were generated with")
+	var ClientRoles []string
+	clientCmd.Flags().StringSliceVar(&ClientRoles, "roles", []string{}, "Roles the resolvers were generated with, adds includeDeleted queries with --roles includeDeleted=ROLE")
+
+	var OpenAPI string
+	restCmd.Flags().StringVar(&OpenAPI, "openapi", "openapi.json", "Path of the OpenAPI document the routes are added to")
+
+	var Provider, Server string
+	initCmd.Flags().StringVar(&Provider, "provider", "postgresql", "Prisma datasource provider: postgresql, mysql, sqlite, sqlserver or cockroachdb")
+	initCmd.Flags().StringVar(&Server, "server", "apollo", "GraphQL server: apollo or yoga")
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
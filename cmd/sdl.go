@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/resolvers"
+	"github.com/tk04/genql/sdl"
+)
+
+var sdlCmd = &cobra.Command{
+	Use:   "sdl",
+	Short: "Generate a GraphQL SDL schema for the Prisma models",
+	Long:  "Generate a GraphQL schema file with the object types, input types, enums and root fields that the resolvers command generates for every Prisma model.\n\n Usage: genql sdl [--output schema.graphql] [--Except op | --Only op].",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		except, _ := cmd.Flags().GetStringArray("Except")
+		only, _ := cmd.Flags().GetStringArray("Only")
+		values, _ := cmd.Flags().GetStringSlice("roles")
+		roles := resolvers.ParseRoles(values)
+		include, err := resolvers.SelectOperations(except, only, roles)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		schema := sdl.Render(prismaUtil.LoadSchema(), include, roles)
+		if err := os.WriteFile(output, []byte(schema), 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/trpc"
+)
+
+var trpcCmd = &cobra.Command{
+	Use:   "trpc",
+	Short: "Generate tRPC routers for Prisma Models",
+	Long:  "Generate a tRPC router with get, list, create, update and delete procedures for each model given, and add it to the appRouter.\n\n Usage: genql trpc [model names].",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		schema := prismaUtil.LoadSchema()
+		for _, name := range args {
+			model, ok := schema.Model(name)
+			if !ok {
+				fmt.Printf("Model (%s) not found in prisma.schema\n", name)
+				os.Exit(1)
+			}
+			if _, ok := model.IdField(); !ok {
+				fmt.Printf("Model (%s) has no id field\n", name)
+				os.Exit(1)
+			}
+			writeTRPCRouter(model, schema)
+		}
+		writeAppRouter()
+	},
+}
+
+const (
+	trpcPath        = "./src/trpc/"
+	trpcRoutersPath = trpcPath + "routers/"
+)
+
+// writeTRPCRouter writes the router of a model to src/trpc/routers/<Model>.ts,
+// and the module creating the procedures if it doesn't exist yet.
+func writeTRPCRouter(model prismaUtil.Model, schema prismaUtil.Schema) {
+	if err := os.MkdirAll(trpcRoutersPath, os.ModePerm); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if _, err := os.Stat(trpcPath + "trpc.ts"); err != nil {
+		if err := os.WriteFile(trpcPath+"trpc.ts", []byte(trpc.Init), 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+	filePath := trpcRoutersPath + model.Name + ".ts"
+	if _, err := os.Stat(filePath); err == nil {
+		fmt.Printf("file (%s) already exists\n", filePath)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filePath, []byte(trpc.Router(model, schema)), 0644); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// writeAppRouter rewrites src/trpc/routers/index.ts to merge every router in
+// the directory, so routers generated earlier are kept.
+func writeAppRouter() {
+	entries, err := os.ReadDir(trpcRoutersPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".ts")
+		if entry.IsDir() || name == entry.Name() || name == "index" {
+			continue
+		}
+		names = append(names, name)
+	}
+	if err := os.WriteFile(trpcRoutersPath+"index.ts", []byte(trpc.AppRouter(names)), 0644); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/zod"
+)
+
+var zodCmd = &cobra.Command{
+	Use:   "zod",
+	Short: "Generate Zod schemas for Prisma Models",
+	Long:  "Generate Zod schemas validating the create and update inputs of Prisma Models.\n\n Usage: genql zod [model names].",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		schema := prismaUtil.LoadSchema()
+		for _, name := range args {
+			model, ok := schema.Model(name)
+			if !ok {
+				fmt.Printf("Model (%s) not found in prisma.schema\n", name)
+				os.Exit(1)
+			}
+			if err := writeZodSchemas(model, schema); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+const schemasPath = "./src/schemas/"
+
+// writeZodSchemas writes the Zod schemas of a model to src/schemas/<Model>.ts.
+func writeZodSchemas(model prismaUtil.Model, schema prismaUtil.Schema) error {
+	if err := os.MkdirAll(schemasPath, os.ModePerm); err != nil {
+		return err
+	}
+	filePath := schemasPath + model.Name + ".ts"
+	if _, err := os.Stat(filePath); err == nil {
+		return fmt.Errorf("file (%s) already exists", filePath)
+	}
+	return os.WriteFile(filePath, []byte(zod.Schemas(model, schema)), 0644)
+}
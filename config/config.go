@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// FileName is the project configuration file, read from the directory genql
+// is run in. Every setting is optional.
+const FileName = "genql.json"
+
+type Config struct {
+	Lint    LintConfig        `json:"lint"`
+	Scalars map[string]Scalar `json:"scalars"` // keyed by Prisma type, e.g. "BigInt"
+	Model   ModelConfig       `json:"model"`
+}
+
+// ModelConfig sets the defaults of the model command's flags.
+type ModelConfig struct {
+	Timestamps bool `json:"timestamps"`
+}
+
+// Scalar configures the GraphQL scalar used for a Prisma type. Empty values
+// keep genql's defaults.
+type Scalar struct {
+	GraphQL string `json:"graphql"` // name of the scalar in the schema
+	TSType  string `json:"tsType"`  // TypeScript type of generated properties
+	Import  string `json:"import"`  // exported GraphQLScalarType
+	From    string `json:"from"`    // module to import from, empty for the generated scalars.ts
+}
+
+type LintConfig struct {
+	Rules map[string]bool `json:"rules"` // rule name -> enabled
+}
+
+func Load() Config {
+	conf := Config{}
+	f, err := os.ReadFile(FileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return conf
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := json.Unmarshal(f, &conf); err != nil {
+		fmt.Printf("invalid %s: %s\n", FileName, err)
+		os.Exit(1)
+	}
+	return conf
+}
@@ -0,0 +1,65 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tk04/genql/prismaUtil"
+)
+
+type Issue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", i.File, i.Line, i.Message, i.Rule)
+}
+
+type Rule struct {
+	Name        string
+	Description string
+	check       func(schema prismaUtil.Schema) []Issue
+}
+
+var Rules = []Rule{
+	{"model-id", "models must have an @id or @@id", checkModelId},
+	{"relation-backref", "relation fields need a back-reference on the related model", checkBackRefs},
+	{"fk-index", "foreign key scalars should be covered by an index", checkFKIndexes},
+	{"string-id-default", "String ids should have a default value", checkStringIds},
+	{"naming", "models and enums are PascalCase, fields are camelCase", checkNaming},
+	{"reserved-name", "names must not clash with reserved GraphQL names", checkReserved},
+	{"enum-case", "enum values are SCREAMING_CASE", checkEnumCase},
+	{"duplicate-map", "@map and @@map names must be unique", checkDuplicateMaps},
+}
+
+func FindRule(name string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Run checks the schema against every rule for which enabled returns true.
+// Issues are sorted by line.
+func Run(schema prismaUtil.Schema, file string, enabled func(rule string) bool) []Issue {
+	issues := []Issue{}
+	for _, r := range Rules {
+		if !enabled(r.Name) {
+			continue
+		}
+		for _, issue := range r.check(schema) {
+			issue.File = file
+			issue.Rule = r.Name
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
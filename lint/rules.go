@@ -0,0 +1,213 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+)
+
+var (
+	pascalCase    = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	camelCase     = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	screamingCase = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	mapName       = regexp.MustCompile(`^"([^"]*)"`)
+)
+
+// type names that are reserved by the GraphQL spec or by the root types
+var reservedTypes = map[string]struct{}{
+	"Query":        {},
+	"Mutation":     {},
+	"Subscription": {},
+	"String":       {},
+	"Int":          {},
+	"Float":        {},
+	"Boolean":      {},
+	"ID":           {},
+}
+
+func checkModelId(schema prismaUtil.Schema) []Issue {
+	issues := []Issue{}
+	for _, m := range schema.Models {
+		if _, ok := m.IdField(); ok || len(m.AttributeArgs("@@id")) > 0 || m.Composite() {
+			continue
+		}
+		issues = append(issues, Issue{Line: m.Line, Message: fmt.Sprintf("model %s has no @id field", m.Name)})
+	}
+	return issues
+}
+
+func checkBackRefs(schema prismaUtil.Schema) []Issue {
+	issues := []Issue{}
+	for _, m := range schema.Models {
+		for _, f := range m.Fields {
+			// composite types are embedded, so they don't point back
+			related, ok := schema.Model(f.NPType)
+			if f.Typename != prismaUtil.NPType || !ok || related.Composite() {
+				continue
+			}
+			found := false
+			for _, rf := range related.Fields {
+				if rf.NPType == m.Name && !(related.Name == m.Name && rf.Name == f.Name) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				issues = append(issues, Issue{Line: f.Line, Message: fmt.Sprintf("relation field %s.%s has no back-reference on model %s", m.Name, f.Name, related.Name)})
+			}
+		}
+	}
+	return issues
+}
+
+func checkFKIndexes(schema prismaUtil.Schema) []Issue {
+	issues := []Issue{}
+	for _, m := range schema.Models {
+		indexed := [][]string{}
+		for _, name := range []string{"@@index", "@@unique", "@@id"} {
+			for _, args := range m.AttributeArgs(name) {
+				indexed = append(indexed, prismaUtil.BlockAttributeFields(args))
+			}
+		}
+		for _, f := range m.Fields {
+			if f.HasAttribute("@unique") || f.HasAttribute("@id") {
+				indexed = append(indexed, []string{f.Name})
+			}
+		}
+
+		for _, f := range m.Fields {
+			fks := f.RelationFields()
+			if len(fks) == 0 || coveredBy(fks, indexed) {
+				continue
+			}
+			issues = append(issues, Issue{Line: f.Line, Message: fmt.Sprintf("foreign key %s.%s is not indexed, add @@index([%s])", m.Name, strings.Join(fks, ", "), strings.Join(fks, ", "))})
+		}
+	}
+	return issues
+}
+
+// coveredBy reports whether the fields are a prefix of one of the indexes.
+func coveredBy(fields []string, indexes [][]string) bool {
+	for _, index := range indexes {
+		if len(index) < len(fields) {
+			continue
+		}
+		covered := true
+		for i, f := range fields {
+			if index[i] != f {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+func checkStringIds(schema prismaUtil.Schema) []Issue {
+	issues := []Issue{}
+	for _, m := range schema.Models {
+		id, ok := m.IdField()
+		if !ok || id.Typename != prismaUtil.StringType || id.HasAttribute("@default") {
+			continue
+		}
+		issues = append(issues, Issue{Line: id.Line, Message: fmt.Sprintf("String id %s.%s has no @default, use @default(uuid()) or @default(cuid())", m.Name, id.Name)})
+	}
+	return issues
+}
+
+func checkNaming(schema prismaUtil.Schema) []Issue {
+	issues := []Issue{}
+	for _, m := range schema.Models {
+		if !pascalCase.MatchString(m.Name) {
+			issues = append(issues, Issue{Line: m.Line, Message: fmt.Sprintf("model %s should be PascalCase", m.Name)})
+		}
+		for _, f := range m.Fields {
+			if !camelCase.MatchString(f.Name) {
+				issues = append(issues, Issue{Line: f.Line, Message: fmt.Sprintf("field %s.%s should be camelCase", m.Name, f.Name)})
+			}
+		}
+	}
+	for _, e := range schema.Enums {
+		if !pascalCase.MatchString(e.Name) {
+			issues = append(issues, Issue{Line: e.Line, Message: fmt.Sprintf("enum %s should be PascalCase", e.Name)})
+		}
+	}
+	return issues
+}
+
+func checkReserved(schema prismaUtil.Schema) []Issue {
+	issues := []Issue{}
+	reserved := func(name string, line int, kind string) {
+		if _, ok := reservedTypes[name]; ok || strings.HasPrefix(name, "__") {
+			issues = append(issues, Issue{Line: line, Message: fmt.Sprintf("%s name %s is reserved in GraphQL", kind, name)})
+		}
+	}
+	for _, m := range schema.Models {
+		reserved(m.Name, m.Line, "model")
+		for _, f := range m.Fields {
+			if strings.HasPrefix(f.Name, "__") {
+				issues = append(issues, Issue{Line: f.Line, Message: fmt.Sprintf("field name %s.%s is reserved in GraphQL", m.Name, f.Name)})
+			}
+		}
+	}
+	for _, e := range schema.Enums {
+		reserved(e.Name, e.Line, "enum")
+	}
+	return issues
+}
+
+func checkEnumCase(schema prismaUtil.Schema) []Issue {
+	issues := []Issue{}
+	for _, e := range schema.Enums {
+		for _, v := range e.Values {
+			if !screamingCase.MatchString(v.Name) {
+				issues = append(issues, Issue{Line: v.Line, Message: fmt.Sprintf("enum value %s.%s should be SCREAMING_CASE", e.Name, v.Name)})
+			}
+		}
+	}
+	return issues
+}
+
+func checkDuplicateMaps(schema prismaUtil.Schema) []Issue {
+	issues := []Issue{}
+	tables := map[string]string{}
+	for _, m := range schema.Models {
+		table := m.Name
+		for _, args := range m.AttributeArgs("@@map") {
+			table = mappedName(args, table)
+		}
+		if other, ok := tables[table]; ok {
+			issues = append(issues, Issue{Line: m.Line, Message: fmt.Sprintf("model %s maps to %q, which is already used by %s", m.Name, table, other)})
+		}
+		tables[table] = m.Name
+
+		columns := map[string]string{}
+		for _, f := range m.Fields {
+			if f.Typename == prismaUtil.NPType && schema.IsModel(f.NPType) {
+				continue
+			}
+			column := f.Name
+			if args, ok := f.AttributeArgs("@map"); ok {
+				column = mappedName(args, column)
+			}
+			if other, ok := columns[column]; ok {
+				issues = append(issues, Issue{Line: f.Line, Message: fmt.Sprintf("field %s.%s maps to %q, which is already used by %s", m.Name, f.Name, column, other)})
+			}
+			columns[column] = f.Name
+		}
+	}
+	return issues
+}
+
+func mappedName(args string, fallback string) string {
+	args = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(args), "name:"))
+	if match := mapName.FindStringSubmatch(args); match != nil {
+		return match[1]
+	}
+	return fallback
+}
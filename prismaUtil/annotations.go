@@ -0,0 +1,118 @@
+package prismaUtil
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// annotations that can follow a field in the model DSL, e.g.
+// `email:string:unique@email` or `name:string@min(2)@max(50)`. The value says
+// whether the annotation takes a numeric argument.
+var ANNOTATIONS = map[string]bool{
+	"email":     false,
+	"uuid":      false,
+	"url":       false,
+	"min":       true,
+	"max":       true,
+	"hidden":    false,
+	"readonly":  false,
+	"writeonly": false,
+}
+
+// prefix of annotations in the /// doc comments they are written to
+// schema.prisma as. It is optional in the model DSL.
+const annotationPrefix = "@genql."
+
+var annotationFormat = regexp.MustCompile(`^(\w+)(?:\((\d+)\))?$`)
+
+// trailing annotation of a DSL value, e.g. `@max(50)` or `@genql.max(50)`
+var dslAnnotation = regexp.MustCompile(`@(genql\.)?(\w+)(?:\((\d+)\))?$`)
+
+// splitAnnotations splits the annotations off the end of a DSL value. Only
+// known annotation names are split off without the prefix, so a default
+// value such as "admin@example" keeps its "@".
+func splitAnnotations(value string) (string, []string) {
+	annotations := []string{}
+	for {
+		match := dslAnnotation.FindStringSubmatchIndex(value)
+		if match == nil {
+			break
+		}
+		prefixed := match[2] != -1
+		name := value[match[4]:match[5]]
+		annotation := value[match[4]:]
+		takesArg, ok := ANNOTATIONS[name]
+		if !ok && !prefixed {
+			break
+		}
+		if !ok || takesArg != (match[6] != -1) {
+			fmt.Printf("invalid annotation entered (@%s)\n", annotation)
+			os.Exit(1)
+		}
+		annotations = append([]string{annotation}, annotations...)
+		value = value[:match[0]]
+	}
+	return value, annotations
+}
+
+// checkLimits exits if the field has a min or max annotation but isn't a
+// string or a number.
+func checkLimits(f Field) {
+	for _, name := range []string{"min", "max"} {
+		if _, ok := f.Annotation(name); ok && !f.limited() {
+			fmt.Printf("annotation (@%s) only applies to String, Int, Float and BigInt fields (%s)\n", name, f.Name)
+			os.Exit(1)
+		}
+	}
+}
+
+func (f Field) limited() bool {
+	switch f.Typename {
+	case StringType, IntType, FloatType, BigIntType:
+		return true
+	}
+	return false
+}
+
+// Limit returns the argument of a min or max annotation. They limit the
+// length of strings and the value of numbers, and are ignored on other types.
+func (f Field) Limit(name string) (string, bool) {
+	if !f.limited() {
+		return "", false
+	}
+	return f.Annotation(name)
+}
+
+// parseAnnotations returns the genql annotations in a /// doc comment.
+func parseAnnotations(doc string) []string {
+	annotations := []string{}
+	for _, token := range strings.Fields(doc) {
+		if strings.HasPrefix(token, annotationPrefix) {
+			annotations = append(annotations, strings.TrimPrefix(token, annotationPrefix))
+		}
+	}
+	return annotations
+}
+
+// AnnotationDoc returns the /// doc comment that stores the field's
+// annotations in schema.prisma, or "" if it has none.
+func (f Field) AnnotationDoc() string {
+	if len(f.Annotations) == 0 {
+		return ""
+	}
+	return "/// " + annotationPrefix + strings.Join(f.Annotations, " "+annotationPrefix)
+}
+
+// Annotation returns the argument of the named annotation, if the field has
+// it. Annotations without an argument return "".
+func (f Field) Annotation(name string) (string, bool) {
+	for _, annotation := range f.Annotations {
+		match := annotationFormat.FindStringSubmatch(annotation)
+		if match != nil && match[1] == name {
+			return match[2], true
+		}
+	}
+	return "", false
+}
@@ -3,39 +3,334 @@ package prismaUtil
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/tk04/genql/config"
 )
 
 var MAPPED_TS = map[PrismaType]string{
 	FloatType:    "number",
 	IntType:      "number",
-	BytesType:    "Uint8Array",
-	JsonType:     "any",
+	BytesType:    "Buffer",
+	JsonType:     "Prisma.JsonValue",
 	BooleanType:  "boolean",
 	StringType:   "string",
-	BigIntType:   "number",
+	BigIntType:   "bigint",
 	DateTimeType: "Date",
 }
 
-func (m Model) toTS(optional bool) string {
-	tsType := ""
+// TypeScript types of input fields that differ from MAPPED_TS. Prisma's
+// JsonValue includes null, which Prisma doesn't accept as a Json value.
+var MAPPED_INPUT_TS = map[PrismaType]string{
+	JsonType: "Prisma.InputJsonValue",
+}
+
+var MAPPED_GRAPHQL = map[PrismaType]string{
+	FloatType:    "Float",
+	IntType:      "Int",
+	BytesType:    "Bytes",
+	JsonType:     "JSON",
+	BooleanType:  "Boolean",
+	StringType:   "String",
+	BigIntType:   "BigInt",
+	DateTimeType: "DateTime",
+}
+
+// types that Type-GraphQL can't infer, passed explicitly to @Field
+var MAPPED_SCALARS = map[PrismaType]config.Scalar{
+	BigIntType:   {GraphQL: "BigInt", TSType: "bigint", Import: "GraphQLBigInt", From: "graphql-scalars"},
+	JsonType:     {GraphQL: "JSON", TSType: "Prisma.JsonValue", Import: "GraphQLJSON", From: "graphql-scalars"},
+	BytesType:    {GraphQL: "Bytes", TSType: "Buffer", Import: "BytesScalar"},
+	DateTimeType: {GraphQL: "DateTime", TSType: "Date", Import: "GraphQLISODateTime", From: "type-graphql"},
+}
+
+// module the generated scalars (From == "") are imported from, relative to
+// a model's resolver directory
+const LocalScalars = "../scalars"
+
+// ConfigureScalars overrides the default scalars with the ones set in
+// genql.json, keyed by Prisma type name.
+func ConfigureScalars(scalars map[string]config.Scalar) {
+	for name, conf := range scalars {
+		t, ok := ParsePrismaType(name)
+		scalar, isScalar := MAPPED_SCALARS[t]
+		if !ok || !isScalar {
+			fmt.Printf("invalid scalar type in %s (%s)\n", config.FileName, name)
+			os.Exit(1)
+		}
+		if conf.GraphQL != "" {
+			scalar.GraphQL = conf.GraphQL
+		}
+		if conf.TSType != "" {
+			scalar.TSType = conf.TSType
+			delete(MAPPED_INPUT_TS, t)
+		}
+		if conf.Import != "" {
+			scalar.Import = conf.Import
+			scalar.From = conf.From
+		}
+		MAPPED_SCALARS[t] = scalar
+		MAPPED_TS[t] = scalar.TSType
+		MAPPED_GRAPHQL[t] = scalar.GraphQL
+	}
+}
+
+// GraphQLType returns the named GraphQL type of the field, without list or
+// non-null wrappers. Non-primitive fields use their Prisma type name.
+func (f Field) GraphQLType() string {
+	if f.GlobalId {
+		return "ID"
+	}
+	if f.Typename == NPType {
+		return f.NPType
+	}
+	return MAPPED_GRAPHQL[f.Typename]
+}
+
+// TypeClass returns the TypeScript value Type-GraphQL uses for the field's
+// type, e.g. Int or GraphQLBigInt.
+func (f Field) TypeClass() string {
+	if f.GlobalId {
+		return "ID"
+	}
+	if f.Enum {
+		return f.NPType
+	}
+	if scalar, ok := MAPPED_SCALARS[f.Typename]; ok {
+		return scalar.Import
+	}
+	return MAPPED_GRAPHQL[f.Typename]
+}
+
+// TypeFunc returns the explicit type function passed to @Field and @Arg,
+// e.g. `() => [String]`.
+func (f Field) TypeFunc() string {
+	if f.IsArray {
+		return "() => [" + f.TypeClass() + "]"
+	}
+	return "() => " + f.TypeClass()
+}
+
+// AddImports adds the imports needed by the field's TypeClass.
+func (f Field) AddImports(imports map[string][]string) {
+	if f.GlobalId {
+		imports["type-graphql"] = append(imports["type-graphql"], "ID")
+		return
+	}
+	if f.Enum {
+		imports[LocalEnums] = append(imports[LocalEnums], f.NPType)
+		return
+	}
+	switch f.Typename {
+	case IntType, FloatType:
+		imports["type-graphql"] = append(imports["type-graphql"], f.TypeClass())
+	}
+	scalar, ok := MAPPED_SCALARS[f.Typename]
+	if !ok {
+		return
+	}
+	from := scalar.From
+	if from == "" {
+		from = LocalScalars
+	}
+	imports[from] = append(imports[from], scalar.Import)
+	if strings.HasPrefix(scalar.TSType, "Prisma.") {
+		imports["@prisma/client"] = append(imports["@prisma/client"], "Prisma")
+	}
+}
+
+// TypeMode selects which of the generated GraphQL types a field belongs to.
+type TypeMode uint8
+
+const (
+	ObjectMode TypeMode = iota
+	CreateMode
+	UpdateMode
+	UpdateManyMode // data of updateMany, without the id
+	WhereMode      // equality filter of the bulk operations and count
+)
+
+// InMode reports whether a primitive field is part of the GraphQL type
+// generated for the given mode. Hidden fields are never part of a type,
+// readonly and server-managed fields only of the object type, and writeonly
+// fields only of the create and update inputs. Owner fields are left out of
+// the create and update inputs.
+func (f Field) InMode(mode TypeMode) bool {
+	_, hidden := f.Annotation("hidden")
+	_, readonly := f.Annotation("readonly")
+	_, writeonly := f.Annotation("writeonly")
+	isId := f.HasAttribute("@id")
+	switch {
+	case hidden:
+		return false
+	case mode == ObjectMode:
+		return !writeonly
+	case mode == UpdateMode && isId: // id required for update operation
+		return true
+	case readonly || f.ServerManaged():
+		return false
+	case f.Owner && mode != WhereMode:
+		return false
+	case mode == WhereMode:
+		// lists and Json can't be matched by equality in a Prisma filter
+		return !writeonly && !f.IsArray && f.Typename != JsonType
+	case mode == UpdateManyMode && isId:
+		return false
+	case mode == CreateMode && isId:
+		// autoincrement ids are assigned by the database
+		args, _ := f.AttributeArgs("@default")
+		return args != "autoincrement()"
+	}
+	return true
+}
+
+// ServerManaged reports whether the field is set by Prisma or the generated
+// resolvers rather than by clients: @updatedAt, createdAt @default(now())
+// and the soft-delete timestamp.
+func (f Field) ServerManaged() bool {
+	if f.Typename != DateTimeType {
+		return false
+	}
+	if f.HasAttribute("@updatedAt") {
+		return true
+	}
+	if args, ok := f.AttributeArgs("@default"); ok && f.Name == "createdAt" && args == "now()" {
+		return true
+	}
+	return f.Name == SoftDeleteField && f.IsOptional
+}
+
+// Nullable reports whether the field is nullable in the GraphQL type
+// generated for the given mode. Fields with a default may be left out of
+// create inputs, but always have a value when read.
+func (f Field) Nullable(mode TypeMode) bool {
+	if f.GlobalId {
+		return false
+	}
+	switch mode {
+	case ObjectMode:
+		return f.IsOptional
+	case UpdateMode:
+		return strings.Index(f.Attribute, "@id") == -1 // id required for update operation
+	case UpdateManyMode, WhereMode:
+		return true
+	}
+	return f.IsOptional || strings.Index(f.Attribute, "@default") != -1
+}
+
+var nativeLength = regexp.MustCompile(`@db\.N?(?:VarChar|Char)\((\d+)\)`)
+
+// NativeLength returns the length of a @db.VarChar(n) or @db.Char(n) column.
+func (f Field) NativeLength() (string, bool) {
+	if match := nativeLength.FindStringSubmatch(f.Attribute); match != nil {
+		return match[1], true
+	}
+	return "", false
+}
+
+// Validators returns the class-validator decorators of the field in the input
+// type generated for mode. Object types are not validated.
+func (f Field) Validators(mode TypeMode) []string {
+	decorators := []string{}
+	if mode == ObjectMode || mode == WhereMode || f.GlobalId {
+		return decorators
+	}
+	add := func(name string, args ...string) {
+		if f.IsArray {
+			args = append(args, "{ each: true }")
+		}
+		decorators = append(decorators, "@"+name+"("+strings.Join(args, ", ")+")")
+	}
+	// placeholder for the options that come before the validation options
+	placeholder := func(value string) []string {
+		if f.IsArray {
+			return []string{value}
+		}
+		return nil
+	}
+
+	if f.Nullable(mode) {
+		decorators = append(decorators, "@IsOptional()")
+	}
+	if f.Typename == IntType {
+		add("IsInt")
+	}
+	if f.Enum {
+		add("IsEnum", f.NPType)
+	}
+	if _, ok := f.Annotation("email"); ok {
+		add("IsEmail", placeholder("{}")...)
+	}
+	if _, ok := f.Annotation("url"); ok {
+		add("IsUrl", placeholder("{}")...)
+	}
+	if _, ok := f.Annotation("uuid"); ok || (f.Typename == StringType && strings.Contains(f.Attribute, "@default(uuid())")) {
+		add("IsUUID", placeholder("\"all\"")...)
+	}
+
+	isString := f.Typename == StringType
+	if min, ok := f.Limit("min"); ok && isString {
+		add("MinLength", min)
+	} else if ok {
+		add("Min", min)
+	}
+	max, ok := f.Limit("max")
+	if length, native := f.NativeLength(); !ok && native {
+		max, ok = length, true
+	}
+	if ok && isString {
+		add("MaxLength", max)
+	} else if ok {
+		add("Max", max)
+	}
+	return decorators
+}
+
+// FieldsIn returns the primitive fields of the type generated for mode. In
+// create inputs, foreign keys of the given relations are optional.
+func (m Model) FieldsIn(mode TypeMode, relations ...Relation) []Field {
+	fields := []Field{}
+	fks := ForeignKeys(relations)
 	for _, field := range m.Fields {
-		// skip non-primative types
-		if field.Typename == NPType {
+		// skip non-primative types other than enums
+		if field.Typename == NPType && !field.Enum {
+			continue
+		}
+		if !field.InMode(mode) {
 			continue
 		}
+		// foreign keys can be set through the nested relation instead
+		if _, ok := fks[field.Name]; ok && mode == CreateMode {
+			field.IsOptional = true
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
 
-		if optional && strings.Index(field.Attribute, "@id") != -1 { // id required for update operation
-			tsType += "\t@Field()\n\t" + field.Name
-		} else if optional || field.IsOptional || strings.Index(field.Attribute, "@default") != -1 {
-			tsType += "\t@Field({ nullable: true })\n\t" + field.Name
+func (m Model) toTS(mode TypeMode, relations ...Relation) string {
+	tsType := ""
+	for _, field := range m.FieldsIn(mode, relations...) {
+		for _, validator := range field.Validators(mode) {
+			tsType += "\t" + validator + "\n"
+		}
+		if field.Nullable(mode) {
+			tsType += "\t" + fieldDecorator(field.TypeFunc(), "{ nullable: true }") + "\n\t" + field.Name
 			tsType += "?"
 		} else {
-			tsType += "\t@Field()\n\t" + field.Name
+			tsType += "\t" + fieldDecorator(field.TypeFunc(), "") + "\n\t" + field.Name
 		}
 		tsType += ": "
 
 		typename, ok := MAPPED_TS[field.Typename]
+		if input, isInput := MAPPED_INPUT_TS[field.Typename]; isInput && mode != ObjectMode {
+			typename = input
+		}
+		if field.Enum {
+			typename, ok = field.NPType, true
+		}
 		if !ok {
 			fmt.Println("invalid type encountered")
 			os.Exit(1)
@@ -47,25 +342,108 @@ func (m Model) toTS(optional bool) string {
 		}
 		tsType += "\n"
 	}
+	tsType += relationsTS(relations, mode)
 	tsType += "}"
 	return tsType
 }
+
+func fieldDecorator(typeFunc string, options string) string {
+	args := []string{}
+	for _, arg := range []string{typeFunc, options} {
+		if arg != "" {
+			args = append(args, arg)
+		}
+	}
+	return "@Field(" + strings.Join(args, ", ") + ")"
+}
+
+// TypeImports returns the names imported by the model's types.ts, keyed by
+// module.
+func (m Model) TypeImports() map[string][]string {
+	imports := map[string][]string{
+		"type-graphql": {"Field", "InputType", "ObjectType"},
+	}
+	m.addImports(imports, ObjectMode, CreateMode, UpdateMode, UpdateManyMode, WhereMode)
+	return imports
+}
+
+func (m Model) addImports(imports map[string][]string, modes ...TypeMode) {
+	for _, field := range m.Fields {
+		if field.Typename == NPType && !field.Enum {
+			continue
+		}
+		for _, mode := range modes {
+			if !field.InMode(mode) {
+				continue
+			}
+			field.AddImports(imports)
+			for _, validator := range field.Validators(mode) {
+				imports["class-validator"] = append(imports["class-validator"], validator[1:strings.Index(validator, "(")])
+			}
+		}
+	}
+}
+
+// RenderImports renders import statements with modules and names sorted.
+func RenderImports(imports map[string][]string) string {
+	modules := []string{}
+	for module := range imports {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	ts := ""
+	for _, module := range modules {
+		seen := map[string]struct{}{}
+		names := []string{}
+		for _, name := range imports[module] {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		ts += "import { " + strings.Join(names, ", ") + " } from \"" + module + "\";\n"
+	}
+	return ts
+}
+
 func (m Model) ObjectType() string {
 	objectType := "@ObjectType()\nexport class " + m.Name + "{\n"
-	objectType += m.toTS(false)
+	if id, ok := m.IdField(); ok && id.GlobalId {
+		objectType = "@ObjectType({ implements: Node })\nexport class " + m.Name + "{\n"
+	}
+	objectType += m.toTS(ObjectMode)
 
 	return objectType
 }
-func (m Model) CreateInputType() string {
+
+// CreateInputType returns the create input, with nested writes for the
+// given relations.
+func (m Model) CreateInputType(relations ...Relation) string {
 	inputType := "@InputType()\nexport class " + "create" + m.Name + "Input " + "{\n"
-	inputType += m.toTS(false)
+	inputType += m.toTS(CreateMode, relations...)
 
 	return inputType
 }
 
-func (m Model) UpdateInputType() string {
+func (m Model) UpdateInputType(relations ...Relation) string {
 	inputType := "@InputType()\nexport class " + "update" + m.Name + "Input " + "{\n"
 
-	inputType += m.toTS(true)
+	inputType += m.toTS(UpdateMode, relations...)
+	return inputType
+}
+
+func (m Model) UpdateManyInputType() string {
+	inputType := "@InputType()\nexport class " + "updateMany" + m.Name + "Input " + "{\n"
+
+	inputType += m.toTS(UpdateManyMode)
+	return inputType
+}
+
+func (m Model) WhereInputType() string {
+	inputType := "@InputType()\nexport class " + m.Name + "WhereInput " + "{\n"
+
+	inputType += m.toTS(WhereMode)
 	return inputType
 }
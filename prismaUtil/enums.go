@@ -0,0 +1,31 @@
+package prismaUtil
+
+// module the enums used by generated types are registered in, relative to a
+// model's resolver directory
+const LocalEnums = "../enums"
+
+// WithEnums returns the model with its enum fields marked, so they are part
+// of the generated types.
+func (s Schema) WithEnums(m Model) Model {
+	model := Model{Name: m.Name, Kind: m.Kind, Fields: []Field{}, Attributes: m.Attributes, Line: m.Line}
+	for _, f := range m.Fields {
+		if f.Typename == NPType && s.IsEnum(f.NPType) {
+			f.Enum = true
+		}
+		model.Fields = append(model.Fields, f)
+	}
+	return model
+}
+
+// EnumNames returns the enums used by the model's fields marked by WithEnums.
+func (m Model) EnumNames() []string {
+	seen := map[string]struct{}{}
+	names := []string{}
+	for _, f := range m.Fields {
+		if _, ok := seen[f.NPType]; f.Enum && !ok {
+			seen[f.NPType] = struct{}{}
+			names = append(names, f.NPType)
+		}
+	}
+	return names
+}
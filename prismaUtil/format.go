@@ -0,0 +1,206 @@
+package prismaUtil
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const indent = "  "
+
+// Format lays out a Prisma schema the way `prisma format` does: blocks are
+// separated by a single blank line, field names, types and attributes are
+// aligned into columns, attribute arguments are spaced consistently and
+// block attributes are sorted at the end of their block.
+func Format(src []byte) []byte {
+	out := []string{}
+	lines := strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		match := blockHeader.FindStringSubmatch(strings.TrimSpace(stripComment(line)))
+		if match == nil {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" && (len(out) == 0 || out[len(out)-1] == "") {
+				continue
+			}
+			out = append(out, trimmed)
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(stripComment(lines[end])) != "}" {
+			end++
+		}
+		if len(out) > 0 && out[len(out)-1] != "" && !strings.HasPrefix(out[len(out)-1], "//") {
+			out = append(out, "")
+		}
+		out = append(out, match[1]+" "+match[2]+" {"+trailingComment(line))
+		out = append(out, formatBlock(match[1], lines[i+1:end])...)
+		out = append(out, "}")
+		if end+1 < len(lines) && strings.TrimSpace(lines[end+1]) != "" {
+			out = append(out, "")
+		}
+		i = end
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+// FormatSchemaFile formats the project's schema.prisma in place.
+func FormatSchemaFile() {
+	path := GetSchemaPath()
+	f, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("schema.prisma does not exist in %s\n", path)
+		os.Exit(1)
+	}
+	formatted := Format(f)
+	if string(formatted) == string(f) {
+		return
+	}
+	if err := os.WriteFile(path, formatted, 0644); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+type formatRow struct {
+	columns []string
+	comment string
+}
+
+func formatBlock(kind string, lines []string) []string {
+	out := []string{}
+	attributes := []string{}
+	// rows separated by blank lines are aligned independently
+	group := []formatRow{}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		code := strings.TrimSpace(stripComment(trimmed))
+		switch {
+		case trimmed == "":
+			out = append(out, alignRows(group)...)
+			group = []formatRow{}
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+		case code == "":
+			group = append(group, formatRow{comment: trimmed})
+		case strings.HasPrefix(code, "@@"):
+			attributes = append(attributes, normalizeAttributes(splitTokens(code))+trailingComment(trimmed))
+		default:
+			group = append(group, formatRow{columns: formatColumns(kind, code), comment: strings.TrimSpace(trailingComment(trimmed))})
+		}
+	}
+	out = append(out, alignRows(group)...)
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+
+	if len(attributes) > 0 {
+		sort.Strings(attributes)
+		if len(out) > 0 {
+			out = append(out, "")
+		}
+		for _, attr := range attributes {
+			out = append(out, indent+attr)
+		}
+	}
+	return out
+}
+
+func formatColumns(kind string, code string) []string {
+	if kind == "datasource" || kind == "generator" {
+		key, value, _ := strings.Cut(code, "=")
+		return []string{strings.TrimSpace(key), "= " + strings.TrimSpace(value)}
+	}
+	tokens := splitTokens(code)
+	columns := []string{tokens[0]}
+	if kind == "enum" {
+		return append(columns, normalizeAttributes(tokens[1:]))
+	}
+	if len(tokens) > 1 {
+		columns = append(columns, tokens[1])
+	}
+	return append(columns, normalizeAttributes(tokens[2:]))
+}
+
+func alignRows(rows []formatRow) []string {
+	widths := []int{}
+	for _, row := range rows {
+		for i, col := range row.columns {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if len(col) > widths[i] {
+				widths[i] = len(col)
+			}
+		}
+	}
+	out := []string{}
+	for _, row := range rows {
+		if row.columns == nil {
+			out = append(out, indent+row.comment)
+			continue
+		}
+		line := ""
+		for i, col := range row.columns {
+			if i == len(row.columns)-1 {
+				line += col
+			} else {
+				line += col + strings.Repeat(" ", widths[i]-len(col)+1)
+			}
+		}
+		line = strings.TrimRight(line, " ")
+		if row.comment != "" {
+			line += " " + row.comment
+		}
+		out = append(out, indent+line)
+	}
+	return out
+}
+
+func normalizeAttributes(tokens []string) string {
+	attrs := []string{}
+	for _, token := range tokens {
+		attrs = append(attrs, normalizeAttribute(token))
+	}
+	return strings.Join(attrs, " ")
+}
+
+// normalizeAttribute drops whitespace outside of string literals and puts
+// a single space after every comma and colon.
+func normalizeAttribute(attr string) string {
+	out := ""
+	quoted := false
+	for i := 0; i < len(attr); i++ {
+		c := attr[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(attr):
+			out += attr[i : i+2]
+			i++
+			continue
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == ' ' || c == '\t':
+			continue
+		case c == ',' || c == ':':
+			out += string(c) + " "
+			continue
+		}
+		out += string(c)
+	}
+	return out
+}
+
+func trailingComment(line string) string {
+	code := stripComment(line)
+	if len(code) == len(line) {
+		return ""
+	}
+	return " " + strings.TrimSpace(line[len(code):])
+}
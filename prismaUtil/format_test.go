@@ -0,0 +1,43 @@
+package prismaUtil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Each testdata/format/<name>.prisma input is formatted and compared with
+// <name>.golden. Set UPDATE_GOLDEN=1 to rewrite the golden files.
+func TestFormat(t *testing.T) {
+	inputs, err := filepath.Glob("testdata/format/*.prisma")
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("no format fixtures found: %v", err)
+	}
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".prisma")
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := strings.TrimSuffix(input, ".prisma") + ".golden"
+			got := Format(src)
+			if os.Getenv("UPDATE_GOLDEN") != "" {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Format(%s) =\n%s\nwant\n%s", input, got, want)
+			}
+			if again := Format(got); string(again) != string(got) {
+				t.Errorf("Format is not idempotent for %s:\n%s", input, again)
+			}
+		})
+	}
+}
@@ -4,18 +4,19 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"strings"
 )
 
 var MAPPED_ATTRIB = map[string]string{
-	"ai":     "@default(autoincrement())",
-	"uuid":   "@default(uuid())",
-	"true":   "@default(true)",
-	"false":  "@default(false)",
-	"unique": "@unique",
+	"ai":        "@default(autoincrement())",
+	"uuid":      "@default(uuid())",
+	"true":      "@default(true)",
+	"false":     "@default(false)",
+	"unique":    "@unique",
+	"now":       "@default(now())",
+	"updatedAt": "@updatedAt",
 }
 
 var MAPPED_TYPES = map[string]PrismaType{
@@ -43,6 +44,8 @@ const (
 	NPType // non-primative types
 )
 
+const SchemaFile = "prisma/schema.prisma"
+
 func GetSchemaPath() string {
 	cmd := exec.Command("pwd")
 
@@ -55,7 +58,18 @@ func GetSchemaPath() string {
 		os.Exit(1)
 	}
 	path := out.String()
-	return path[:len(path)-1] + "/prisma/schema.prisma"
+	return path[:len(path)-1] + "/" + SchemaFile
+}
+
+// ParsePrismaType maps a type name as written in schema.prisma (e.g.
+// "DateTime") to its PrismaType.
+func ParsePrismaType(name string) (PrismaType, bool) {
+	for t := StringType; t < NPType; t++ {
+		if s, _ := t.String(); s == name {
+			return t, true
+		}
+	}
+	return NPType, false
 }
 func (p PrismaType) String() (string, error) {
 	switch p {
@@ -88,21 +102,86 @@ type Field struct {
 	IsArray    bool
 	Attribute  string
 	NPType     string // non-primative types, optional
+	Line       int    // line in schema.prisma, set when parsed from the schema
+	GlobalId   bool   // Relay global id, see Model.WithGlobalId
+	Enum       bool   // NPType is an enum, see Schema.WithEnums
+	Owner      bool   // set to the authenticated user, see Model.WithOwner
+
+	Annotations []string // e.g. "email" or "max(50)", stored as /// @genql. doc comments
 }
 
 type Model struct {
-	Name   string
-	Fields []Field
+	Name       string
+	Kind       string // block keyword in schema.prisma: model (or ""), view or type
+	Fields     []Field
+	Attributes []string // block attributes, e.g. @@index([userId])
+	Line       int
+}
+
+// Composite reports whether the model is a MongoDB composite type, which is
+// embedded in other models and has no id or relations of its own.
+func (m Model) Composite() bool {
+	return m.Kind == "type"
 }
 
 func (p *Model) String() string {
-	stringVal := "\nmodel " + p.Name + " {\n"
+	lines := []string{}
 	for _, field := range p.Fields {
-		stringVal += "\t" + field.String() + "\n"
+		if doc := field.AnnotationDoc(); doc != "" {
+			lines = append(lines, doc)
+		}
+		lines = append(lines, field.String())
+	}
+	lines = append(lines, p.Attributes...)
+
+	return "model " + p.Name + " {\n" + strings.Join(formatBlock("model", lines), "\n") + "\n}"
+}
+
+// SoftDeleteField marks records as deleted instead of removing them. The
+// resolvers of models with this field filter out deleted records.
+const SoftDeleteField = "deletedAt"
+
+// AddSoftDelete adds the soft-delete timestamp and an index on it, unless
+// the model already has them.
+func (p *Model) AddSoftDelete() {
+	if _, ok := p.Field(SoftDeleteField); !ok {
+		p.AddField(Field{Name: SoftDeleteField, Typename: DateTimeType, IsOptional: true})
+	}
+	index := "@@index([" + SoftDeleteField + "])"
+	for _, attr := range p.Attributes {
+		if attr == index {
+			return
+		}
+	}
+	p.Attributes = append(p.Attributes, index)
+}
+
+// AddTimestamps adds createdAt and updatedAt fields managed by Prisma,
+// unless the model already has them.
+func (p *Model) AddTimestamps() {
+	if _, ok := p.Field("createdAt"); !ok {
+		p.AddField(Field{Name: "createdAt", Typename: DateTimeType, Attribute: MAPPED_ATTRIB["now"]})
 	}
-	stringVal += "}"
+	if _, ok := p.Field("updatedAt"); !ok {
+		p.AddField(Field{Name: "updatedAt", Typename: DateTimeType, Attribute: MAPPED_ATTRIB["updatedAt"]})
+	}
+}
 
-	return stringVal
+func (m Model) SoftDelete() bool {
+	f, ok := m.Field(SoftDeleteField)
+	return ok && f.ServerManaged()
+}
+
+// WithOwner returns the model with the named field marked as its owner. The
+// resolvers set it to the authenticated user, so it is left out of the
+// create and update inputs.
+func (m Model) WithOwner(name string) Model {
+	owned := Model{Name: m.Name, Kind: m.Kind, Fields: []Field{}, Attributes: m.Attributes, Line: m.Line}
+	for _, f := range m.Fields {
+		f.Owner = f.Owner || (name != "" && f.Name == name)
+		owned.Fields = append(owned.Fields, f)
+	}
+	return owned
 }
 
 func (p *Model) AddField(field Field) {
@@ -127,6 +206,9 @@ func (p *Field) String() string {
 		prismaType += "?"
 	}
 
+	if p.Attribute == "" {
+		return p.Name + " " + prismaType
+	}
 	return p.Name + " " + prismaType + " " + p.Attribute
 }
 
@@ -152,7 +234,9 @@ func ParseField(str string) Field { // string of the form typename:type:default_
 		fmt.Printf("Invalid format enetered (%s)\n", strings.Join(values, ":"))
 		os.Exit(1)
 	}
-	parsedT := Field{Name: values[0], IsOptional: false, IsArray: false, Attribute: ""}
+	var annotations []string
+	values[len(values)-1], annotations = splitAnnotations(values[len(values)-1])
+	parsedT := Field{Name: values[0], IsOptional: false, IsArray: false, Attribute: "", Annotations: annotations}
 	splitType := strings.Split(values[1], "[]")
 	if len(splitType) == 2 {
 		parsedT.IsArray = true
@@ -171,7 +255,7 @@ func ParseField(str string) Field { // string of the form typename:type:default_
 			// check if its an id type
 			if values[1] == "id" {
 				parsedT.Typename = parseID(values)
-				parsedT.Attribute += "@id\t"
+				parsedT.Attribute += "@id "
 			} else {
 				fmt.Printf("invalid type entered (%s), please enter a valid type\n", splitType[0])
 				os.Exit(1)
@@ -181,12 +265,14 @@ func ParseField(str string) Field { // string of the form typename:type:default_
 		}
 	}
 
+	checkLimits(parsedT)
+
 	// parse attributes
 	if len(values) == 3 {
 		if attribute, ok := MAPPED_ATTRIB[values[2]]; ok {
 			parsedT.Attribute += attribute
 		} else {
-			parsedT.Attribute = "@default (\"" + values[2] + "\")"
+			parsedT.Attribute = "@default(\"" + values[2] + "\")"
 		}
 	}
 
@@ -207,161 +293,53 @@ func ParseModel(modelName string, values []string) Model {
 }
 
 func GetIdType(modelName string) PrismaType {
-	f, err := os.ReadFile(GetSchemaPath())
-	if err != nil {
-		fmt.Println("file not found. Create a prisma.schema file @ the following path: ", GetSchemaPath())
-		os.Exit(1)
-	}
-	index := bytes.Index(f, []byte(modelName))
-
-	index2 := bytes.Index(f[index:], []byte("@id"))
-	var IdType string
-	for i := index2 + index; i >= 0; i-- {
-		if (f[i] >= 65 && f[i] <= 90) || (f[i] >= 97 && f[i] <= 122) {
-			IdType = string(f[i]) + IdType
-		} else if len(IdType) > 0 {
-			break
-		}
-	}
-
-	typename, ok := MAPPED_TYPES[strings.ToLower(IdType)]
-	if !ok {
-		fmt.Printf("unknown Id type (%s)\n", IdType)
+	model := GetModel(modelName)
+	idField, ok := model.IdField()
+	if !ok || idField.Typename == NPType {
+		fmt.Printf("unknown Id type for model (%s)\n", modelName)
 		os.Exit(1)
 	}
-	return typename
+	return idField.Typename
 }
 
+// AddField inserts a field at the end of an existing model in schema.prisma.
 func AddField(field Field, modelName string) {
-	var newBytes []byte
-
-	f, err := os.OpenFile(GetSchemaPath(), os.O_RDWR, 0644)
-	defer f.Close()
+	path := GetSchemaPath()
+	f, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Println("file not found. Create a schema.prisma file @ the following path: ", GetSchemaPath())
+		fmt.Println("file not found. Create a schema.prisma file @ the following path: ", path)
 		os.Exit(1)
 	}
-	buffer := make([]byte, 1)
-	i, err := f.Read(buffer)
-	offset := i
-	currStr := ""
-	for err != io.EOF {
-		newBytes = append(newBytes, buffer...)
-		if (buffer[0] >= 65 && buffer[0] <= 90) || (buffer[0] >= 97 && buffer[0] <= 122) {
-			currStr += string(buffer)
-		} else {
-			if currStr == modelName {
-				break
-			}
-			currStr = ""
+	lines := strings.Split(string(f), "\n")
+	for i, line := range lines {
+		match := blockHeader.FindStringSubmatch(strings.TrimSpace(stripComment(line)))
+		if match == nil || match[1] != "model" || match[2] != modelName {
+			continue
 		}
-		i, err = f.Read(buffer)
-		offset += i
-	}
-	if err == io.EOF {
-		fmt.Printf("Model name %s does not exist in schema.prisma, make sure to create the model first\n", modelName)
-		os.Exit(1)
-	}
-	for err != io.EOF {
-		i, err = f.Read(buffer)
-		if buffer[0] == 125 {
-			newBytes = append(newBytes, []byte("\t"+field.String()+"\n")...)
-			break
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(stripComment(lines[end])) != "}" {
+			end++
 		}
-		newBytes = append(newBytes, buffer...)
-	}
-	for err != io.EOF {
-		newBytes = append(newBytes, buffer...)
-		i, err = f.Read(buffer)
+		lines = append(lines[:end], append([]string{indent + field.String()}, lines[end:]...)...)
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
 	}
-	f.Truncate(0)
-	f.Seek(0, 0)
-	f.Write(newBytes)
+	fmt.Printf("Model name %s does not exist in schema.prisma, make sure to create the model first\n", modelName)
+	os.Exit(1)
 }
 
 func findModel(modelName string) bool {
-	f, err := os.ReadFile(GetSchemaPath())
-	if err != nil {
-		fmt.Printf("schema.prisma does not exist in %s", GetSchemaPath())
-		os.Exit(1)
-	}
-	index := bytes.Index(f, []byte("model "+modelName))
-	if index == -1 {
-		return false
-	}
-	return true
+	return LoadSchema().IsModel(modelName)
 }
+
 func GetModel(modelName string) Model {
-	f, err := os.ReadFile(GetSchemaPath())
-	if err != nil {
-		fmt.Printf("schema.prisma does not exist in %s", GetSchemaPath())
-		os.Exit(1)
-	}
-	model := Model{Name: modelName, Fields: []Field{}}
-	index := bytes.Index(f, []byte("model "+modelName))
-	if index == -1 {
+	model, ok := LoadSchema().Model(modelName)
+	if !ok {
 		fmt.Printf("Model (%s) not found in prisma.schema\n", modelName)
 		os.Exit(1)
 	}
-	index2 := bytes.Index(f[index:], []byte("\n"))
-
-	// var char byte
-	element := ""
-	for _, b := range f[index+index2:] {
-
-		if b == '}' {
-			break
-		}
-		if b != 10 && b != 32 {
-			element += string(b)
-		}
-		if b == ' ' && len(element) > 0 && element[len(element)-1] != '-' {
-			element += "-"
-		}
-		if b == 10 {
-			if len(element) > 0 {
-				model.Fields = append(model.Fields, parseField2(element))
-			}
-			element = ""
-
-		}
-	}
-
 	return model
 }
-
-// parse field seperated by dashed value
-func parseField2(dValue string) Field {
-	values := strings.Split(dValue, "-")
-	if len(values) < 2 {
-		fmt.Println("error parsing values")
-		os.Exit(1)
-	}
-	field := Field{Name: values[0]}
-	splitType := strings.Split(values[1], "[]")
-	if len(splitType) == 2 {
-		field.IsArray = true
-	}
-	splitType = strings.Split(strings.Join(splitType, ""), "?")
-	if len(splitType) == 2 {
-		field.IsOptional = true
-	}
-
-	lowT := strings.ToLower(splitType[0])
-	if t, ok := MAPPED_TYPES[lowT]; ok {
-		field.Typename = t
-	} else {
-		field.Typename = NPType
-		field.NPType = splitType[0]
-	}
-
-	if len(values) >= 3 {
-		attrib := ""
-		for _, att := range values[2:] {
-			attrib += att + " "
-		}
-		field.Attribute = attrib
-	}
-
-	return field
-}
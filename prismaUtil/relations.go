@@ -0,0 +1,202 @@
+package prismaUtil
+
+import (
+	"strings"
+)
+
+// Relation is a relation field of a model together with the model it points
+// to. Create and update inputs take nested writes for every relation.
+type Relation struct {
+	Model   string // name of the model the field belongs to
+	Field   Field
+	Related Model
+}
+
+// RelationInput is an input class generated for a nested relation write,
+// e.g. FriendUserCreateNestedInput with create and connect fields.
+type RelationInput struct {
+	Name   string
+	Fields []RelationInputField
+}
+
+type RelationInputField struct {
+	Name   string
+	Type   string // input class, or Boolean
+	IsList bool
+}
+
+// Relations returns the relation fields of the model whose related model has
+// an id to connect records by.
+func (s Schema) Relations(m Model) []Relation {
+	relations := []Relation{}
+	for _, f := range m.Fields {
+		related, ok := s.Model(f.NPType)
+		if f.Typename != NPType || !ok {
+			continue
+		}
+		if _, ok := related.IdField(); !ok {
+			continue
+		}
+		relations = append(relations, Relation{Model: m.Name, Field: f, Related: related})
+	}
+	return relations
+}
+
+// ForeignKeys returns the scalar fields set through the model's relations.
+func ForeignKeys(relations []Relation) map[string]struct{} {
+	fks := map[string]struct{}{}
+	for _, rel := range relations {
+		for _, fk := range rel.Field.RelationFields() {
+			fks[fk] = struct{}{}
+		}
+	}
+	return fks
+}
+
+func (r Relation) prefix() string {
+	return r.Model + strings.ToUpper(r.Field.Name[:1]) + r.Field.Name[1:]
+}
+
+func (r Relation) UniqueInputName() string {
+	return r.prefix() + "WhereUniqueInput"
+}
+
+func (r Relation) CreateInputName() string {
+	return r.prefix() + "CreateInput"
+}
+
+func (r Relation) CreateNestedInputName() string {
+	return r.prefix() + "CreateNestedInput"
+}
+
+func (r Relation) UpdateNestedInputName() string {
+	return r.prefix() + "UpdateNestedInput"
+}
+
+// UniqueModel returns a model with only the related model's id, used to
+// connect and disconnect records.
+func (r Relation) UniqueModel() Model {
+	id, _ := r.Related.IdField()
+	id.Attribute = "@id"
+	id.Annotations = nil
+	return Model{Name: r.UniqueInputName(), Fields: []Field{id}}
+}
+
+// CreateModel returns the related model without the foreign keys pointing
+// back to the model, which Prisma sets itself in a nested create.
+func (r Relation) CreateModel() Model {
+	back := map[string]struct{}{}
+	for _, f := range r.Related.Fields {
+		// a self relation's back-reference is the other field of the pair
+		if f.NPType == r.Model && (r.Related.Name != r.Model || f.Name != r.Field.Name) {
+			for _, fk := range f.RelationFields() {
+				back[fk] = struct{}{}
+			}
+		}
+	}
+	model := Model{Name: r.CreateInputName(), Fields: []Field{}}
+	for _, f := range r.Related.Fields {
+		if _, ok := back[f.Name]; !ok && f.Typename != NPType {
+			model.Fields = append(model.Fields, f)
+		}
+	}
+	return model
+}
+
+// Creatable reports whether related records can be created through the
+// relation. GraphQL doesn't allow input types without fields.
+func (r Relation) Creatable() bool {
+	for _, f := range r.CreateModel().Fields {
+		if f.InMode(CreateMode) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Relation) CreateNestedInput() RelationInput {
+	input := RelationInput{Name: r.CreateNestedInputName()}
+	if r.Creatable() {
+		input.Fields = append(input.Fields, RelationInputField{Name: "create", Type: r.CreateInputName(), IsList: r.Field.IsArray})
+	}
+	input.Fields = append(input.Fields, RelationInputField{Name: "connect", Type: r.UniqueInputName(), IsList: r.Field.IsArray})
+	return input
+}
+
+func (r Relation) UpdateNestedInput() RelationInput {
+	input := r.CreateNestedInput()
+	input.Name = r.UpdateNestedInputName()
+	if r.Field.IsArray {
+		input.Fields = append(input.Fields,
+			RelationInputField{Name: "disconnect", Type: r.UniqueInputName(), IsList: true},
+			RelationInputField{Name: "set", Type: r.UniqueInputName(), IsList: true},
+		)
+	} else if r.Field.IsOptional {
+		input.Fields = append(input.Fields, RelationInputField{Name: "disconnect", Type: "Boolean"})
+	}
+	return input
+}
+
+// NestedInputName returns the nested write input a relation field takes in
+// the input type generated for mode.
+func (r Relation) NestedInputName(mode TypeMode) string {
+	if mode == CreateMode {
+		return r.CreateNestedInputName()
+	}
+	return r.UpdateNestedInputName()
+}
+
+// RelationTypes returns the input classes used by the nested relation
+// writes of the create and update inputs.
+func RelationTypes(relations []Relation) string {
+	types := []string{}
+	for _, rel := range relations {
+		unique := rel.UniqueModel()
+		types = append(types, "@InputType()\nexport class "+unique.Name+" {\n"+unique.toTS(UpdateMode))
+		if create := rel.CreateModel(); rel.Creatable() {
+			types = append(types, "@InputType()\nexport class "+create.Name+" {\n"+create.toTS(CreateMode))
+		}
+		types = append(types, rel.CreateNestedInput().TS(), rel.UpdateNestedInput().TS())
+	}
+	return strings.Join(types, "\n")
+}
+
+// RelationImports adds the imports of the RelationTypes.
+func RelationImports(relations []Relation, imports map[string][]string) {
+	for _, rel := range relations {
+		rel.UniqueModel().addImports(imports, UpdateMode)
+		rel.CreateModel().addImports(imports, CreateMode)
+		imports["class-validator"] = append(imports["class-validator"], "IsOptional", "ValidateNested")
+	}
+}
+
+func (in RelationInput) TS() string {
+	ts := "@InputType()\nexport class " + in.Name + " {\n"
+	for _, f := range in.Fields {
+		typeFunc := f.Type
+		tsType := f.Type
+		if f.Type == "Boolean" {
+			tsType = "boolean"
+		}
+		if f.IsList {
+			typeFunc = "[" + typeFunc + "]"
+			tsType += "[]"
+		}
+		ts += "\t@IsOptional()\n"
+		if f.Type != "Boolean" {
+			ts += "\t@ValidateNested()\n"
+		}
+		ts += "\t@Field(() => " + typeFunc + ", { nullable: true })\n\t" + f.Name + "?: " + tsType + "\n"
+	}
+	return ts + "}"
+}
+
+// relationsTS returns the nested write fields of the create and update inputs.
+func relationsTS(relations []Relation, mode TypeMode) string {
+	ts := ""
+	for _, rel := range relations {
+		name := rel.NestedInputName(mode)
+		ts += "\t@IsOptional()\n\t@ValidateNested()\n\t@Field(() => " + name + ", { nullable: true })\n\t" + rel.Field.Name + "?: " + name + "\n"
+	}
+	return ts
+}
@@ -0,0 +1,18 @@
+package prismaUtil
+
+// WithGlobalId returns the model with its id exposed as a Relay global id:
+// an opaque ID encoding the model name and the record's id. Only the object
+// type and the update input use it; create inputs and filters keep the raw id.
+func (m Model) WithGlobalId() Model {
+	relay := Model{Name: m.Name, Kind: m.Kind, Fields: []Field{}, Attributes: m.Attributes, Line: m.Line}
+	for _, f := range m.Fields {
+		if f.HasAttribute("@id") {
+			f.GlobalId = true
+			f.Typename = StringType
+			f.IsArray = false
+			f.Annotations = nil
+		}
+		relay.Fields = append(relay.Fields, f)
+	}
+	return relay
+}
@@ -0,0 +1,335 @@
+package prismaUtil
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Schema is a parsed schema.prisma file. Line numbers on models, enums and
+// fields are 1-based and refer to the file the schema was read from.
+type Schema struct {
+	Path   string
+	Models []Model
+	Enums  []Enum
+}
+
+type Enum struct {
+	Name       string
+	Values     []EnumValue
+	Attributes []string
+	Line       int
+}
+
+type EnumValue struct {
+	Name      string
+	Attribute string
+	Line      int
+}
+
+var blockHeader = regexp.MustCompile(`^(model|enum|type|view|datasource|generator)\s+(\w+)\s*\{$`)
+
+// LoadSchema reads and parses the schema.prisma file of the current project.
+func LoadSchema() Schema {
+	path := GetSchemaPath()
+	f, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("schema.prisma does not exist in %s\n", path)
+		os.Exit(1)
+	}
+	schema := ParseSchema(f)
+	schema.Path = path
+	return schema
+}
+
+func ParseSchema(src []byte) Schema {
+	schema := Schema{}
+	lines := strings.Split(string(src), "\n")
+	for i := 0; i < len(lines); i++ {
+		match := blockHeader.FindStringSubmatch(strings.TrimSpace(stripComment(lines[i])))
+		if match == nil {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(stripComment(lines[end])) != "}" {
+			end++
+		}
+		switch match[1] {
+		case "model", "view", "type":
+			model := parseModelBlock(lines[i+1:end], i+2)
+			model.Name = match[2]
+			model.Kind = match[1]
+			model.Line = i + 1
+			schema.Models = append(schema.Models, model)
+		case "enum":
+			enum := parseEnumBlock(lines[i+1:end], i+2)
+			enum.Name = match[2]
+			enum.Line = i + 1
+			schema.Enums = append(schema.Enums, enum)
+		}
+		i = end
+	}
+	return schema
+}
+
+func parseModelBlock(lines []string, firstLine int) Model {
+	model := Model{Fields: []Field{}}
+	annotations := []string{}
+	for i, line := range lines {
+		if doc := strings.TrimSpace(line); strings.HasPrefix(doc, "///") {
+			annotations = append(annotations, parseAnnotations(doc[3:])...)
+			continue
+		}
+		trimmed := strings.TrimSpace(stripComment(line))
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "@@") {
+			model.Attributes = append(model.Attributes, strings.Join(splitTokens(trimmed), " "))
+			annotations = []string{}
+			continue
+		}
+		field := parseSchemaField(splitTokens(trimmed))
+		field.Line = firstLine + i
+		if len(annotations) > 0 {
+			field.Annotations = annotations
+		}
+		model.Fields = append(model.Fields, field)
+		annotations = []string{}
+	}
+	return model
+}
+
+func parseEnumBlock(lines []string, firstLine int) Enum {
+	enum := Enum{}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(stripComment(line))
+		if trimmed == "" {
+			continue
+		}
+		tokens := splitTokens(trimmed)
+		if strings.HasPrefix(trimmed, "@@") {
+			enum.Attributes = append(enum.Attributes, strings.Join(tokens, " "))
+			continue
+		}
+		enum.Values = append(enum.Values, EnumValue{Name: tokens[0], Attribute: strings.Join(tokens[1:], " "), Line: firstLine + i})
+	}
+	return enum
+}
+
+func parseSchemaField(tokens []string) Field {
+	field := Field{Name: tokens[0]}
+	if len(tokens) < 2 {
+		return field
+	}
+	typename := tokens[1]
+	if strings.HasSuffix(typename, "?") {
+		field.IsOptional = true
+		typename = strings.TrimSuffix(typename, "?")
+	}
+	if strings.HasSuffix(typename, "[]") {
+		field.IsArray = true
+		typename = strings.TrimSuffix(typename, "[]")
+	}
+	if t, ok := ParsePrismaType(typename); ok {
+		field.Typename = t
+	} else {
+		field.Typename = NPType
+		field.NPType = typename
+	}
+	field.Attribute = strings.Join(tokens[2:], " ")
+	return field
+}
+
+// splitTokens splits a schema line on whitespace that is outside of quotes,
+// parentheses and brackets. An argument list separated from its attribute
+// name, as in `@default ("x")`, is joined back onto the attribute.
+func splitTokens(line string) []string {
+	tokens := []string{}
+	current := ""
+	depth := 0
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted:
+			if c == '\\' && i+1 < len(line) {
+				current += string(c)
+				i++
+				c = line[i]
+			} else if c == '"' {
+				quoted = false
+			}
+		case c == '"':
+			quoted = true
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case (c == ' ' || c == '\t') && depth == 0:
+			if current != "" {
+				tokens = append(tokens, current)
+			}
+			current = ""
+			continue
+		}
+		if c == '(' && depth == 1 && current == "" && len(tokens) > 0 && strings.HasPrefix(tokens[len(tokens)-1], "@") {
+			current = tokens[len(tokens)-1]
+			tokens = tokens[:len(tokens)-1]
+		}
+		current += string(c)
+	}
+	if current != "" {
+		tokens = append(tokens, current)
+	}
+	return tokens
+}
+
+// stripComment removes a trailing `//` comment that is not inside a string.
+func stripComment(line string) string {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && quoted:
+			i++
+		case line[i] == '"':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(line[i:], "//"):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func (s Schema) Model(name string) (Model, bool) {
+	for _, m := range s.Models {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Model{}, false
+}
+
+func (s Schema) Enum(name string) (Enum, bool) {
+	for _, e := range s.Enums {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Enum{}, false
+}
+
+func (s Schema) IsModel(name string) bool {
+	_, ok := s.Model(name)
+	return ok
+}
+
+func (s Schema) IsEnum(name string) bool {
+	_, ok := s.Enum(name)
+	return ok
+}
+
+// AttributeArgs returns the argument list of the attribute with the given
+// name (e.g. "@relation"), without the surrounding parentheses.
+func (f Field) AttributeArgs(name string) (string, bool) {
+	return attributeArgs(splitTokens(f.Attribute), name)
+}
+
+func (f Field) HasAttribute(name string) bool {
+	_, ok := f.AttributeArgs(name)
+	return ok
+}
+
+// RelationFields returns the scalar fields referenced by the `fields`
+// argument of the field's @relation attribute.
+func (f Field) RelationFields() []string {
+	args, ok := f.AttributeArgs("@relation")
+	if !ok {
+		return nil
+	}
+	return namedList(args, "fields")
+}
+
+func (m Model) Field(name string) (Field, bool) {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// IdField returns the field marked with @id, if the model has one.
+func (m Model) IdField() (Field, bool) {
+	for _, f := range m.Fields {
+		if f.HasAttribute("@id") {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// AttributeArgs returns the argument list of the block attribute with the
+// given name (e.g. "@@index").
+func (m Model) AttributeArgs(name string) []string {
+	args := []string{}
+	for _, attr := range m.Attributes {
+		if a, ok := attributeArgs([]string{attr}, name); ok {
+			args = append(args, a)
+		}
+	}
+	return args
+}
+
+// BlockAttributeFields returns the field list of a block attribute argument
+// list such as `[userId, postId], map: "idx"` or `fields: [userId]`.
+func BlockAttributeFields(args string) []string {
+	if fields := namedList(args, "fields"); fields != nil {
+		return fields
+	}
+	args = strings.TrimSpace(args)
+	end := strings.Index(args, "]")
+	if !strings.HasPrefix(args, "[") || end == -1 {
+		return nil // not a list, or an unterminated one such as `[a, b`
+	}
+	return splitList(args[1:end])
+}
+
+func attributeArgs(tokens []string, name string) (string, bool) {
+	for _, token := range tokens {
+		if token == name {
+			return "", true
+		}
+		if strings.HasPrefix(token, name+"(") && strings.HasSuffix(token, ")") {
+			return token[len(name)+1 : len(token)-1], true
+		}
+	}
+	return "", false
+}
+
+var listArg = regexp.MustCompile(`(\w+)\s*:\s*\[([^\]]*)\]`)
+
+func namedList(args string, name string) []string {
+	for _, match := range listArg.FindAllStringSubmatch(args, -1) {
+		if match[1] == name {
+			return splitList(match[2])
+		}
+	}
+	return nil
+}
+
+func splitList(list string) []string {
+	values := []string{}
+	for _, v := range strings.Split(list, ",") {
+		// drop argument lists such as `name(sort: Desc)`
+		if i := strings.Index(v, "("); i != -1 {
+			v = v[:i]
+		}
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,58 @@
+package resolvers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// authorized returns the @Authorized decorator for an operation, or "" when
+// the resolver is generated without authorization.
+func (r Resolver) authorized(operation string) string {
+	if !r.Auth {
+		return ""
+	}
+	roles := []string{}
+	for _, role := range r.Roles[operation] {
+		roles = append(roles, "\""+role+"\"")
+	}
+	return "\t@Authorized(" + strings.Join(roles, ", ") + ")\n"
+}
+
+// ParseRoles parses role assignments of the form operation=ROLE.
+func ParseRoles(values []string) map[string][]string {
+	roles := map[string][]string{}
+	for _, val := range values {
+		operation, role, ok := strings.Cut(val, "=")
+		if !ok || role == "" {
+			fmt.Printf("invalid role format (%s), expected operation=ROLE\n", val)
+			os.Exit(1)
+		}
+		if !IsOperation(operation) {
+			fmt.Printf("unknown operation (%s)\n", operation)
+			os.Exit(1)
+		}
+		roles[operation] = append(roles[operation], role)
+	}
+	return roles
+}
+
+func createAuthChecker() error {
+	pathName := "./src/resolvers/authChecker.ts"
+	if checkFileExists(pathName) {
+		return nil
+	}
+	f, err := os.OpenFile(pathName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	imports := "import { AuthChecker } from \"type-graphql\";\nimport { context } from \"./context\";"
+	checker := "// Pass to buildSchema({ authChecker }). Set context.user when building the\n// context, e.g. from a verified session or token.\n" +
+		"export const authChecker: AuthChecker<context> = ({ context: { user } }, roles) => {\n" +
+		"\tif (!user) {\n\t\treturn false;\n\t}\n" +
+		"\tif (roles.length === 0) {\n\t\treturn true;\n\t}\n" +
+		"\treturn user.roles.some((role) => roles.includes(role));\n};"
+	_, err = f.WriteString(imports + "\n\n" + checker + "\n")
+	return err
+}
@@ -0,0 +1,44 @@
+package resolvers
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+)
+
+// createEnums registers the enums the model uses in enums.ts, which every
+// model's types import them from. Enums registered by earlier runs are kept.
+func createEnums(dir string, graphqlModule string, model prismaUtil.Model) error {
+	pathName := dir + "enums.ts"
+	names := model.EnumNames()
+	if len(names) == 0 {
+		return nil
+	}
+	if !checkFileExists(pathName) {
+		if err := writeFile(pathName, "import { registerEnumType } from \""+graphqlModule+"\";\n"); err != nil {
+			return err
+		}
+	}
+	f, err := os.ReadFile(pathName)
+	if err != nil {
+		return err
+	}
+	ts := string(f)
+	for _, name := range names {
+		if strings.Contains(ts, "registerEnumType("+name+",") {
+			continue
+		}
+		// imports go after the last import, registrations at the end
+		lines := strings.Split(ts, "\n")
+		imports := 0
+		for i, line := range lines {
+			if strings.HasPrefix(line, "import ") {
+				imports = i + 1
+			}
+		}
+		lines = append(lines[:imports], append([]string{"import { " + name + " } from \"@prisma/client\";"}, lines[imports:]...)...)
+		ts = strings.Join(lines, "\n") + "\nregisterEnumType(" + name + ", { name: \"" + name + "\" });\nexport { " + name + " };\n"
+	}
+	return writeFile(pathName, ts)
+}
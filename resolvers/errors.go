@@ -0,0 +1,107 @@
+package resolvers
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+)
+
+// createErrors writes the error classes and helpers shared by the generated
+// resolvers.
+func createErrors(dir string) error {
+	pathName := dir + "errors.ts"
+	if checkFileExists(pathName) {
+		return nil
+	}
+	f, err := os.OpenFile(pathName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	imports := "import { Prisma } from \"@prisma/client\";\nimport { GraphQLError } from \"graphql\";"
+	classes := "export class NotFoundError extends GraphQLError {\n" +
+		"\tconstructor(model: string, id?: unknown, message?: string) {\n" +
+		"\t\tsuper(id === undefined ? message ?? `${model} not found` : `${model} ${id} not found`, { extensions: { code: \"NOT_FOUND\", model, id } });\n\t}\n}\n\n" +
+		"export class UniqueConstraintError extends GraphQLError {\n" +
+		"\tconstructor(model: string, fields: string[]) {\n" +
+		"\t\tsuper(`${model} with the same ${fields.join(\", \")} already exists`, { extensions: { code: \"UNIQUE_VIOLATION\", model, fields } });\n\t}\n}\n\n" +
+		"export class ForeignKeyError extends GraphQLError {\n" +
+		"\tconstructor(model: string, fields: string[]) {\n" +
+		"\t\tsuper(`${model} references a record that does not exist (${fields.join(\", \")})`, { extensions: { code: \"FOREIGN_KEY_VIOLATION\", model, fields } });\n\t}\n}"
+	handlers := "// Fields named by a Prisma error, from meta.target or meta.field_name.\n" +
+		"const errorFields = (meta?: Record<string, unknown>): string[] => {\n" +
+		"\tconst target = meta?.target ?? meta?.field_name;\n" +
+		"\tif (Array.isArray(target)) {\n\t\treturn target.map(String);\n\t}\n" +
+		"\treturn target === undefined ? [] : [String(target)];\n};\n\n" +
+		"// Rethrows Prisma's known request errors as GraphQL errors with a stable\n// extensions.code, e.g. prisma.user.create(...).catch(prismaError(\"User\"))\n" +
+		"export const prismaError = (model: string, id?: unknown) => (error: unknown): never => {\n" +
+		"\tif (error instanceof Prisma.PrismaClientKnownRequestError) {\n" +
+		"\t\tswitch (error.code) {\n" +
+		"\t\t\tcase \"P2002\":\n\t\t\t\tthrow new UniqueConstraintError(model, errorFields(error.meta));\n" +
+		"\t\t\tcase \"P2003\":\n\t\t\t\tthrow new ForeignKeyError(model, errorFields(error.meta));\n" +
+		"\t\t\tcase \"P2025\":\n\t\t\t\tthrow new NotFoundError(model, id, error.meta?.cause as string | undefined);\n" +
+		"\t\t}\n\t}\n\tthrow error;\n};\n\n" +
+		"// Rethrows Prisma's record not found error as a NotFoundError, e.g.\n// prisma.user.update(...).catch(notFound(\"User\", id))\n" +
+		"export const notFound = (model: string, id: unknown) => (error: unknown): never => {\n" +
+		"\tif (error instanceof Prisma.PrismaClientKnownRequestError && error.code === \"P2025\") {\n" +
+		"\t\tthrow new NotFoundError(model, id);\n\t}\n\tthrow error;\n};"
+	updateData := "// Returns the fields set in an update input. Fields left out of the request\n// are left unchanged, and explicit nulls clear optional fields. Prisma\n// clears Json fields with DbNull instead of null.\n" +
+		"export const updateData = <T extends object>(input: T, required: (keyof T)[], json: (keyof T)[] = []): Partial<T> => {\n" +
+		"\tconst data: Partial<T> = {};\n" +
+		"\tfor (const [key, value] of Object.entries(input) as [keyof T, T[keyof T]][]) {\n" +
+		"\t\tif (value === undefined) {\n\t\t\tcontinue;\n\t\t}\n" +
+		"\t\tif (value === null && required.includes(key)) {\n" +
+		"\t\t\tthrow new GraphQLError(`${String(key)} cannot be null`, { extensions: { code: \"BAD_USER_INPUT\", field: key } });\n\t\t}\n" +
+		"\t\tdata[key] = (value === null && json.includes(key) ? Prisma.DbNull : value) as T[keyof T];\n\t}\n\treturn data;\n};"
+	_, err = f.WriteString(imports + "\n\n" + classes + "\n\n" + handlers + "\n\n" + updateData + "\n")
+	return err
+}
+
+// catch returns the handler chained to an operation's Prisma call, mapping
+// Prisma errors to GraphQL errors unless Errors is off.
+func (r Resolver) catch(id string) string {
+	if !r.Errors {
+		return ""
+	}
+	if id != "" {
+		return ".catch(prismaError(\"" + r.Model.Name + "\", " + id + "))"
+	}
+	return ".catch(prismaError(\"" + r.Model.Name + "\"))"
+}
+
+// catchNotFound returns the catch of a mutation targeting the record with the
+// given id, mapping a missing record to a NotFoundError even without --errors.
+func (r Resolver) catchNotFound(id string) string {
+	if !r.Errors {
+		return ".catch(notFound(\"" + r.Model.Name + "\", " + id + "))"
+	}
+	return r.catch(id)
+}
+
+// targetsRecord reports whether the resolver has a mutation targeting a
+// record by id.
+func (r Resolver) targetsRecord() bool {
+	return r.has("update") || r.has("delete") || r.has("upsert") || (r.has("restore") && r.Model.SoftDelete())
+}
+
+// updateData returns the call building the data of an update from the
+// input's data, passing the fields that can't be set to null and the Json
+// fields Prisma clears with DbNull.
+func (r Resolver) updateData() string {
+	required, json := []string{}, []string{}
+	for _, f := range r.Model.Fields {
+		if (f.Typename == prismaUtil.NPType && !f.Enum) || f.HasAttribute("@id") || f.Name == r.OwnerField || !f.InMode(prismaUtil.UpdateMode) {
+			continue
+		}
+		if !f.IsOptional {
+			required = append(required, "\""+f.Name+"\"")
+		} else if f.Typename == prismaUtil.JsonType {
+			json = append(json, "\""+f.Name+"\"")
+		}
+	}
+	if len(json) > 0 {
+		return "updateData(data, [" + strings.Join(required, ", ") + "], [" + strings.Join(json, ", ") + "])"
+	}
+	return "updateData(data, [" + strings.Join(required, ", ") + "])"
+}
@@ -0,0 +1,157 @@
+package resolvers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	indexPath  = "./src/resolvers/index.ts"
+	schemaPath = "./src/resolvers/schema.ts"
+)
+
+// addToIndex adds a resolver class to the resolvers array exported by
+// index.ts, creating the file if needed. Classes already in the array are
+// left as they are, so regenerating a resolver doesn't add it twice.
+func addToIndex(name string, module string) error {
+	if !checkFileExists(indexPath) {
+		if err := writeFile(indexPath, "export const resolvers = [\n] as const;\n"); err != nil {
+			return err
+		}
+	}
+	f, err := os.ReadFile(indexPath)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(f), "\n")
+	imports, end := 0, -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == name+"," {
+			return nil
+		}
+		if strings.HasPrefix(line, "import ") {
+			imports = i + 1
+		}
+		if strings.HasPrefix(line, "]") {
+			end = i
+		}
+	}
+	if end == -1 {
+		return fmt.Errorf("resolvers array not found in %s", indexPath)
+	}
+	lines = append(lines[:end], append([]string{"\t" + name + ","}, lines[end:]...)...)
+	importLine := "import { " + name + " } from \"" + module + "\";"
+	if imports == 0 {
+		importLine += "\n"
+	}
+	lines = append(lines[:imports], append([]string{importLine}, lines[imports:]...)...)
+	return writeFile(indexPath, strings.Join(lines, "\n"))
+}
+
+// RemoveFromIndex removes resolver classes and their imports from index.ts.
+// buildSchema rejects an empty resolvers array, so once the last class is
+// removed index.ts and schema.ts are deleted instead, and it returns true.
+func RemoveFromIndex(names ...string) (bool, error) {
+	if !checkFileExists(indexPath) {
+		return false, nil
+	}
+	f, err := os.ReadFile(indexPath)
+	if err != nil {
+		return false, err
+	}
+	removed := func(line string) bool {
+		for _, name := range names {
+			if strings.TrimSpace(line) == name+"," || strings.HasPrefix(line, "import { "+name+" } from ") {
+				return true
+			}
+		}
+		return false
+	}
+	lines := []string{}
+	found, start, entries := false, -1, 0
+	for _, line := range strings.Split(string(f), "\n") {
+		if removed(line) {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "export const resolvers = ["):
+			found, start = true, len(lines)
+		case strings.HasPrefix(line, "]"):
+			start = -1
+		case start != -1 && strings.TrimSpace(line) != "":
+			entries++
+		}
+		lines = append(lines, line)
+	}
+	if !found || entries > 0 {
+		return false, writeFile(indexPath, strings.Join(lines, "\n"))
+	}
+	for _, path := range []string{indexPath, schemaPath} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// RemoveNodeLoader removes a model from the loaders of the node query. Once
+// the last relay model is removed, node.ts and relay.ts are deleted and it
+// returns true, so NodeResolver can be removed from index.ts as well.
+func RemoveNodeLoader(modelName string) (bool, error) {
+	pathName := "./src/resolvers/node.ts"
+	if !checkFileExists(pathName) {
+		return false, nil
+	}
+	f, err := os.ReadFile(pathName)
+	if err != nil {
+		return false, err
+	}
+	lines := []string{}
+	found, start, entries := false, -1, 0
+	for _, line := range strings.Split(string(f), "\n") {
+		if strings.HasPrefix(line, "\t"+modelName+": ") {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "const loaders"):
+			found, start = true, len(lines)
+		case strings.HasPrefix(line, "};"):
+			start = -1
+		case start != -1 && strings.TrimSpace(line) != "":
+			entries++
+		}
+		lines = append(lines, line)
+	}
+	if !found || entries > 0 {
+		return false, writeFile(pathName, strings.Join(lines, "\n"))
+	}
+	for _, path := range []string{pathName, "./src/resolvers/relay.ts"} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// WriteSchemaFile writes schema.ts, which builds the schema of the resolvers
+// in index.ts and emits it to schema.graphql. It is rewritten on every run,
+// so the authChecker is passed once one has been generated.
+func WriteSchemaFile() error {
+	imports := "import path from \"path\";\nimport { BuildSchemaOptions, buildSchema } from \"type-graphql\";\n"
+	options := "\t\tresolvers,\n"
+	if checkFileExists("./src/resolvers/authChecker.ts") {
+		imports += "import { authChecker } from \"./authChecker\";\n"
+		options += "\t\tauthChecker,\n"
+	}
+	imports += "import { resolvers } from \"./index\";\n"
+	options += "\t\tvalidate: true,\n\t\temitSchemaFile: path.resolve(__dirname, \"../../schema.graphql\"),\n\t\t...options,\n"
+	schema := "// Builds the schema of every generated resolver. Options such as pubSub are\n// passed through, e.g. createSchema({ pubSub }).\n" +
+		"export const createSchema = (options: Omit<BuildSchemaOptions, \"resolvers\"> = {}) =>\n\tbuildSchema({\n" + options + "\t});\n"
+	return writeFile(schemaPath, imports+"\n"+schema)
+}
+
+// SchemaFileExists reports whether schema.ts was generated by an earlier run.
+func SchemaFileExists() bool {
+	return checkFileExists(schemaPath)
+}
@@ -0,0 +1,244 @@
+package resolvers
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+)
+
+// A NestJS target generates a module per model in src/<model>/, with a
+// service wrapping the same Prisma calls as the Type-GraphQL resolvers and a
+// resolver calling the service. The Prisma client is injected as the
+// PrismaService instead of being passed in the context.
+
+const (
+	nestCommonPath = "./src/common/"
+	nestPrismaPath = "./src/prisma/"
+)
+
+// nestModules maps the modules the Type-GraphQL resolvers import from to
+// their NestJS equivalents, relative to a model's module directory.
+var nestModules = map[string]string{
+	"type-graphql":          "@nestjs/graphql",
+	prismaUtil.LocalScalars: "../common/scalars",
+	prismaUtil.LocalEnums:   "../common/enums",
+	"../errors":             "../common/errors",
+}
+
+// NestDir returns the directory and file name prefix of the model's module,
+// e.g. blog-post for BlogPost.
+func NestDir(modelName string) string {
+	return strings.ToLower(regexp.MustCompile(`([a-z0-9])([A-Z])`).ReplaceAllString(modelName, "${1}-${2}"))
+}
+
+type nestArg struct {
+	name      string
+	decorator string // @Args decorator of the resolver parameter
+	tsType    string
+}
+
+func (a nestArg) param() string {
+	optional := ""
+	if strings.Contains(a.decorator, "nullable: true") {
+		optional = "?"
+	}
+	return a.name + optional + ": " + a.tsType
+}
+
+type nestOp struct {
+	operation string
+	decorator string // @Query or @Mutation of the resolver method
+	field     string // name of the Query or Mutation field
+	args      []nestArg
+	method    method // Type-GraphQL method, its body calls the service's Prisma client
+}
+
+func (r Resolver) nestOps() []nestOp {
+	name := r.Model.Name
+	idField := getIdField(&r.Model)
+	id := nestArg{"id", "@Args(\"id\", { type: " + idField.TypeFunc() + " })", prismaUtil.MAPPED_TS[idField.Typename]}
+	where := nestArg{"where", "@Args(\"where\")", name + "WhereInput"}
+	ops := []nestOp{}
+	for _, val := range r.Functions {
+		switch val {
+		case "get":
+			ops = append(ops, nestOp{val, "@Query(() => " + name + ", { nullable: true })", "get" + name, []nestArg{id}, r.addFunc(idArg(idField))})
+		case "create":
+			ops = append(ops, nestOp{val, "@Mutation(() => " + name + ")", "create" + name, []nestArg{{"input", "@Args(\"input\")", "create" + name + "Input"}}, r.createFunc()})
+		case "update":
+			ops = append(ops, nestOp{val, "@Mutation(() => " + name + ")", "update" + name, []nestArg{{"input", "@Args(\"input\")", "update" + name + "Input"}}, r.updateFunc()})
+		case "delete":
+			ops = append(ops, nestOp{val, "@Mutation(() => " + name + ", { nullable: true })", "delete" + name, []nestArg{id}, r.deleteFunc(idArg(idField))})
+		case "upsert":
+			ops = append(ops, nestOp{val, "@Mutation(() => " + name + ")", "upsert" + name, []nestArg{id, {"input", "@Args(\"input\")", "create" + name + "Input"}}, r.upsertFunc(idArg(idField))})
+		case "createMany":
+			ops = append(ops, nestOp{val, "@Mutation(() => Int)", "createMany" + plural(name), []nestArg{{"inputs", "@Args(\"inputs\", { type: () => [create" + name + "Input] })", "create" + name + "Input[]"}}, r.createManyFunc()})
+		case "updateMany":
+			ops = append(ops, nestOp{val, "@Mutation(() => Int)", "updateMany" + plural(name), []nestArg{where, {"data", "@Args(\"data\")", "updateMany" + name + "Input"}}, r.updateManyFunc()})
+		case "deleteMany":
+			ops = append(ops, nestOp{val, "@Mutation(() => Int)", "deleteMany" + plural(name), []nestArg{where}, r.deleteManyFunc()})
+		case "count":
+			ops = append(ops, nestOp{val, "@Query(() => Int)", "count" + plural(name), []nestArg{{"where", "@Args(\"where\", { nullable: true })", name + "WhereInput"}}, r.countFunc()})
+		case "restore":
+			if r.Model.SoftDelete() {
+				ops = append(ops, nestOp{val, "@Mutation(() => " + name + ", { nullable: true })", "restore" + name, []nestArg{id}, r.restoreFunc(idArg(idField))})
+			}
+		case "includeDeleted":
+			if r.includesDeleted() {
+				ops = append(ops, nestOp{val, "@Query(() => " + name + ", { nullable: true })", "include" + name + "Deleted", []nestArg{id}, r.includeDeletedFunc(idArg(idField))})
+			}
+		}
+	}
+	return ops
+}
+
+// CreateNestFiles writes the model's NestJS module, and the PrismaService,
+// errors and scalars shared by every module if they don't exist yet.
+func (r Resolver) CreateNestFiles() error {
+	dir := NestDir(r.Model.Name)
+	modulePath := "./src/" + dir + "/"
+	for _, path := range []string{modulePath, nestCommonPath, nestPrismaPath} {
+		if err := os.MkdirAll(path, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	files := map[string]string{
+		modulePath + dir + ".types.ts":    r.nestTypes(),
+		modulePath + dir + ".service.ts":  r.nestService(),
+		modulePath + dir + ".resolver.ts": r.nestResolver(),
+		modulePath + dir + ".module.ts":   r.nestModule(),
+	}
+	for filePath := range files {
+		if checkFileExists(filePath) {
+			return fmt.Errorf("file (%s) already exists", filePath)
+		}
+	}
+	for filePath, ts := range files {
+		if err := writeFile(filePath, ts); err != nil {
+			return err
+		}
+	}
+	if err := createPrismaService(); err != nil {
+		return err
+	}
+	if err := createErrors(nestCommonPath); err != nil {
+		return err
+	}
+	if err := createScalars(nestCommonPath, r.Model); err != nil {
+		return err
+	}
+	return createEnums(nestCommonPath, "@nestjs/graphql", r.Model)
+}
+
+func createPrismaService() error {
+	if !checkFileExists(nestPrismaPath + "prisma.service.ts") {
+		err := writeFile(nestPrismaPath+"prisma.service.ts", "import { Injectable, OnModuleInit } from \"@nestjs/common\";\nimport { PrismaClient } from \"@prisma/client\";\n\n"+
+			"@Injectable()\nexport class PrismaService extends PrismaClient implements OnModuleInit {\n"+
+			"\tasync onModuleInit() {\n\t\tawait this.$connect();\n\t}\n}\n")
+		if err != nil {
+			return err
+		}
+	}
+	if !checkFileExists(nestPrismaPath + "prisma.module.ts") {
+		return writeFile(nestPrismaPath+"prisma.module.ts", "import { Module } from \"@nestjs/common\";\nimport { PrismaService } from \"./prisma.service\";\n\n"+
+			"@Module({\n\tproviders: [PrismaService],\n\texports: [PrismaService],\n})\nexport class PrismaModule {}\n")
+	}
+	return nil
+}
+
+// nestImports moves names imported from Type-GraphQL and the generated
+// modules to their NestJS equivalents.
+func nestImports(imports map[string][]string) map[string][]string {
+	moved := map[string][]string{}
+	for module, names := range imports {
+		if nestModule, ok := nestModules[module]; ok {
+			module = nestModule
+		}
+		moved[module] = append(moved[module], names...)
+	}
+	return moved
+}
+
+func (r Resolver) nestTypes() string {
+	imports, types := r.types()
+	return prismaUtil.RenderImports(nestImports(imports)) + "\n" + types
+}
+
+// nestService returns the service wrapping the Prisma calls, with a method
+// per operation named after it, e.g. get or createMany.
+func (r Resolver) nestService() string {
+	imports := map[string][]string{
+		"@nestjs/common":           {"Injectable"},
+		"../prisma/prisma.service": {"PrismaService"},
+	}
+	if r.Errors {
+		imports["../errors"] = append(imports["../errors"], "prismaError")
+	} else if r.targetsRecord() {
+		imports["../errors"] = append(imports["../errors"], "notFound")
+	}
+	if r.has("update") || r.has("updateMany") {
+		imports["../errors"] = append(imports["../errors"], "updateData")
+	}
+	service := r
+	service.client = "this.prisma"
+	methods := []string{}
+	for _, op := range service.nestOps() {
+		params := []string{}
+		for _, arg := range op.args {
+			params = append(params, arg.param())
+			if strings.HasSuffix(arg.tsType, "Input") || strings.HasSuffix(arg.tsType, "Input[]") {
+				imports["./"+NestDir(r.Model.Name)+".types"] = append(imports["./"+NestDir(r.Model.Name)+".types"], strings.TrimSuffix(arg.tsType, "[]"))
+			}
+		}
+		async := ""
+		if op.method.async {
+			async = "async "
+		}
+		methods = append(methods, "\t"+async+op.operation+"("+strings.Join(params, ", ")+") {\n"+op.method.body)
+	}
+	ts := prismaUtil.RenderImports(nestImports(imports)) + "\n"
+	ts += "@Injectable()\nexport class " + r.Model.Name + "Service {\n\tconstructor(private readonly prisma: PrismaService) {}\n\n"
+	return ts + strings.Join(methods, "\n\n") + "\n}\n"
+}
+
+func (r Resolver) nestResolver() string {
+	name := r.Model.Name
+	service := strings.ToLower(name[:1]) + name[1:] + "Service"
+	types := "./" + NestDir(name) + ".types"
+	imports := map[string][]string{
+		"@nestjs/graphql":                 {"Resolver"},
+		types:                             {name},
+		"./" + NestDir(name) + ".service": {name + "Service"},
+	}
+	getIdField(&r.Model).AddImports(imports)
+	methods := []string{}
+	for _, op := range r.nestOps() {
+		imports["@nestjs/graphql"] = append(imports["@nestjs/graphql"], "Args", op.decorator[1:strings.Index(op.decorator, "(")])
+		if strings.Contains(op.decorator, "Int") {
+			imports["@nestjs/graphql"] = append(imports["@nestjs/graphql"], "Int")
+		}
+		params, values := []string{}, []string{}
+		for _, arg := range op.args {
+			params = append(params, arg.decorator+" "+arg.param())
+			values = append(values, arg.name)
+			if strings.Contains(arg.tsType, "Input") {
+				imports[types] = append(imports[types], strings.TrimSuffix(arg.tsType, "[]"))
+			}
+		}
+		methods = append(methods, "\t"+op.decorator+"\n\t"+op.field+"("+strings.Join(params, ", ")+") {\n\t\treturn this."+service+"."+op.operation+"("+strings.Join(values, ", ")+");\n\t}")
+	}
+	ts := prismaUtil.RenderImports(nestImports(imports)) + "\n"
+	ts += "@Resolver(() => " + name + ")\nexport class " + name + "Resolver {\n\tconstructor(private readonly " + service + ": " + name + "Service) {}\n\n"
+	return ts + strings.Join(methods, "\n\n") + "\n}\n"
+}
+
+func (r Resolver) nestModule() string {
+	name := r.Model.Name
+	ts := "import { Module } from \"@nestjs/common\";\nimport { PrismaModule } from \"../prisma/prisma.module\";\n"
+	ts += "import { " + name + "Resolver } from \"./" + NestDir(name) + ".resolver\";\nimport { " + name + "Service } from \"./" + NestDir(name) + ".service\";\n\n"
+	ts += "@Module({\n\timports: [PrismaModule],\n\tproviders: [" + name + "Resolver, " + name + "Service],\n\texports: [" + name + "Service],\n})\n"
+	return ts + "export class " + name + "Module {}\n"
+}
@@ -3,89 +3,388 @@ package resolvers
 import (
 	"errors"
 	"fmt"
+	pluralize "github.com/gertd/go-pluralize"
 	"github.com/tk04/genql/prismaUtil"
 	"os"
+	"regexp"
 	"strings"
 )
 
 type Resolver struct {
 	Functions []string
 	Model     prismaUtil.Model
+
+	Auth       bool                // add @Authorized() to every operation
+	Roles      map[string][]string // roles allowed per operation, e.g. "delete": ["ADMIN"]
+	OwnerField string              // FK limiting operations to the authenticated user's records
+
+	Relations []prismaUtil.Relation // relations written through nested inputs
+	Errors    bool                  // map Prisma errors to GraphQL errors
+
+	Subscriptions bool // publish create, update and delete events
+	Relay         bool // global ids, Node interface and connection query
+
+	client string // Prisma client the operations call, prisma from the context by default
 }
 
-func (r Resolver) CreateFiles() {
-	addTypes(r.Model)
-	createCtx()
+// method is a generated operation: the decorator and signature of the
+// Type-GraphQL resolver method, and the body calling Prisma, which the NestJS
+// service reuses under its own signature.
+type method struct {
+	signature string
+	body      string
+	async     bool
+}
+
+func (m method) String() string {
+	return m.signature + m.body
+}
+
+func (r Resolver) CreateFiles() error {
+	if err := r.addTypes(); err != nil {
+		return err
+	}
+	if err := createCtx(); err != nil {
+		return err
+	}
+	if err := createErrors("./src/resolvers/"); err != nil {
+		return err
+	}
+	if err := createScalars("./src/resolvers/", r.Model); err != nil {
+		return err
+	}
+	if err := createEnums("./src/resolvers/", "type-graphql", r.Model); err != nil {
+		return err
+	}
+	if r.Auth {
+		if err := addCtxField("user", "user?: { id: string | number; roles: string[] };", ""); err != nil {
+			return err
+		}
+		if err := createAuthChecker(); err != nil {
+			return err
+		}
+	}
+	if r.Relay {
+		if err := createRelay(); err != nil {
+			return err
+		}
+		if err := createNode(); err != nil {
+			return err
+		}
+		if err := r.addNodeLoader(); err != nil {
+			return err
+		}
+	}
+	if r.Subscriptions {
+		if err := addCtxField("pubSub", PubSubField, ""); err != nil {
+			return err
+		}
+	}
 
 	resolverPath := "./src/resolvers/" + r.Model.Name
 
 	filePath := resolverPath + "/index.ts"
 	if checkFileExists(filePath) {
-		fmt.Printf("file (%s) already exists\n", filePath)
-		os.Exit(1)
+		return fmt.Errorf("file (%s) already exists", filePath)
 	}
-	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	f.WriteString(r.String())
-	defer f.Close()
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if err := writeFile(filePath, r.String()); err != nil {
+		return err
 	}
 
+	if err := addToIndex(r.Model.Name+"Resolver", "./"+r.Model.Name); err != nil {
+		return err
+	}
+	if r.Relay {
+		if err := addToIndex("NodeResolver", "./node"); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 func (r Resolver) String() string {
 	createInputType := "create" + r.Model.Name + "Input"
 	updateInputType := "update" + r.Model.Name + "Input"
-	headers := "import { Arg, Ctx, Mutation, Query, Resolver } from \"type-graphql\";\n" +
-		"import { context } from \"../context\"\n" +
-		"import { " + r.Model.Name + ", " + createInputType + ", " + updateInputType + " } from \"./types\"\n\n"
+	idField := getIdField(&r.Model)
+	if r.Relay {
+		idField, _ = r.Model.WithGlobalId().IdField()
+	}
+	imports := map[string][]string{
+		"type-graphql": {"Arg", "Ctx", "Mutation", "Query", "Resolver"},
+		"../context":   {"context"},
+		"./types":      {r.Model.Name, createInputType, updateInputType},
+	}
+	if r.HasUpdateManyInput() {
+		imports["./types"] = append(imports["./types"], "updateMany"+r.Model.Name+"Input")
+	}
+	if r.HasWhereInput() {
+		imports["./types"] = append(imports["./types"], r.Model.Name+"WhereInput")
+	}
+	if r.has("createMany") || r.has("updateMany") || r.has("deleteMany") || r.has("count") {
+		imports["type-graphql"] = append(imports["type-graphql"], "Int")
+	}
+	if r.Auth {
+		imports["type-graphql"] = append(imports["type-graphql"], "Authorized")
+	}
+	if r.Errors {
+		imports["../errors"] = append(imports["../errors"], "prismaError")
+	} else if r.targetsRecord() {
+		imports["../errors"] = append(imports["../errors"], "notFound")
+	}
+	if r.has("update") || r.has("updateMany") {
+		imports["../errors"] = append(imports["../errors"], "updateData")
+	}
+	if r.Subscriptions {
+		imports["type-graphql"] = append(imports["type-graphql"], "ResolverFilterData", "Root", "Subscription")
+	}
+	if r.Relay {
+		imports["type-graphql"] = append(imports["type-graphql"], "FieldResolver", "Root")
+		imports["../relay"] = append(imports["../relay"], "toGlobalId")
+		for _, op := range []string{"get", "update", "delete", "upsert", "restore", "includeDeleted"} {
+			if r.has(op) {
+				imports["../relay"] = append(imports["../relay"], "fromGlobalId")
+			}
+		}
+	}
+	if r.Relay && r.has("connection") {
+		imports["type-graphql"] = append(imports["type-graphql"], "Args")
+		imports["../relay"] = append(imports["../relay"], "ConnectionArgs", "paginate")
+		imports["./types"] = append(imports["./types"], r.Model.Name+"Connection")
+	}
+	idField.AddImports(imports)
+	headers := prismaUtil.RenderImports(imports) + "\n"
+	if r.Subscriptions {
+		headers += r.topics()
+	}
 	resolverClass := "@Resolver()\nexport class " + r.Model.Name + "Resolver {\n"
+	if r.Relay {
+		resolverClass = "@Resolver(() => " + r.Model.Name + ")\nexport class " + r.Model.Name + "Resolver {\n" + r.globalIdFunc() + "\n"
+	}
 	ts := headers + resolverClass
-	idType := getIdType(&r.Model)
 	for _, val := range r.Functions {
 		switch val {
 		case "get":
-			ts += addFunc(r.Model.Name, idType) + "\n"
+			ts += r.authorized(val) + r.addFunc(idArg(idField)).String() + "\n"
 		case "create":
-			ts += createFunc(r.Model.Name) + "\n"
+			ts += r.authorized(val) + r.createFunc().String() + "\n"
 		case "update":
-			ts += updateFunc(r.Model.Name) + "\n"
+			ts += r.authorized(val) + r.updateFunc().String() + "\n"
 		case "delete":
-			ts += deleteFunc(r.Model.Name, idType) + "\n"
+			ts += r.authorized(val) + r.deleteFunc(idArg(idField)).String() + "\n"
+		case "upsert":
+			ts += r.authorized(val) + r.upsertFunc(idArg(idField)).String() + "\n"
+		case "createMany":
+			ts += r.authorized(val) + r.createManyFunc().String() + "\n"
+		case "updateMany":
+			ts += r.authorized(val) + r.updateManyFunc().String() + "\n"
+		case "deleteMany":
+			ts += r.authorized(val) + r.deleteManyFunc().String() + "\n"
+		case "count":
+			ts += r.authorized(val) + r.countFunc().String() + "\n"
+		case "restore":
+			if r.Model.SoftDelete() {
+				ts += r.authorized(val) + r.restoreFunc(idArg(idField)).String() + "\n"
+			}
+		case "connection":
+			if r.Relay {
+				ts += r.authorized("get") + r.connectionFunc() + "\n"
+			}
+		case "includeDeleted":
+			if r.includesDeleted() {
+				ts += r.authorized(val) + r.includeDeletedFunc(idArg(idField)).String() + "\n"
+			}
+		}
+	}
+	for _, e := range events {
+		if r.Subscriptions && r.has(e.Operation) {
+			ts += r.authorized("get") + r.subscriptionFunc(e.Event, idField) + "\n"
 		}
 	}
 
 	ts += "}"
 	return ts
 }
-func addFunc(modelName string, idType string) string {
-	// objectType := model.Name
 
-	getQuery := "\t@Query(() => " + modelName + ", { nullable: true })\n\tget" + modelName + "(@Ctx() { prisma }: context, @Arg(\"id\") id: " + idType + "){\n"
-	getFirstQuery := "\t\treturn prisma." + strings.ToLower(modelName) + ".findFirst({\n\t\t\twhere: {\n\t\t\t\tid: id\n\t\t\t},\n\t\t});\n\t}"
-	return getQuery + getFirstQuery
+func (r Resolver) has(operation string) bool {
+	for _, val := range r.Functions {
+		if val == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// HasUpdateManyInput reports whether an operation takes the
+// updateMany<Model>Input.
+func (r Resolver) HasUpdateManyInput() bool {
+	return r.has("updateMany")
+}
+
+// HasWhereInput reports whether an operation takes the <Model>WhereInput.
+func (r Resolver) HasWhereInput() bool {
+	return r.has("updateMany") || r.has("deleteMany") || r.has("count")
+}
+
+// ctxParam returns the context parameter of an operation, destructuring the
+// authenticated user when operations are limited to their owner, and the
+// PubSub when the operation publishes.
+func (r Resolver) ctxParam(operation string) string {
+	names := "prisma"
+	if r.OwnerField != "" {
+		names += ", user"
+	}
+	if r.publishes(operation) {
+		names += ", pubSub"
+	}
+	return "@Ctx() { " + names + " }: context"
+}
+
+// where returns the where clause matching a record by id, and by owner when
+// operations are limited to the authenticated user's records.
+func (r Resolver) where(id string) string {
+	return objectLiteral(r.filters("id: " + r.idValue(id))...)
+}
+
+// filters adds the owner and soft-delete filters to the entries of a where
+// clause.
+func (r Resolver) filters(entries ...string) []string {
+	if r.OwnerField != "" {
+		entries = append(entries, r.OwnerField+": "+r.ownerId())
+	}
+	if r.Model.SoftDelete() {
+		entries = append(entries, prismaUtil.SoftDeleteField+": null")
+	}
+	return entries
+}
+
+func objectLiteral(entries ...string) string {
+	return "{\n\t\t\t\t" + strings.Join(entries, ",\n\t\t\t\t") + "\n\t\t\t}"
+}
+
+// delegate returns the model's Prisma delegate, e.g. prisma.post.
+func (r Resolver) delegate() string {
+	client := r.client
+	if client == "" {
+		client = "prisma"
+	}
+	return client + "." + strings.ToLower(r.Model.Name)
+}
+
+// ownerId returns the authenticated user's id, typed as the owner field.
+func (r Resolver) ownerId() string {
+	owner, _ := r.Model.Field(r.OwnerField)
+	return "user!.id as " + prismaUtil.MAPPED_TS[owner.Typename]
+}
+
+func (r Resolver) addFunc(idParam string) method {
+	modelName := r.Model.Name
+
+	getQuery := "\t@Query(() => " + modelName + ", { nullable: true })\n\tget" + modelName + "(" + r.ctxParam("get") + ", " + idParam + "){\n"
+	getFirstQuery := "\t\treturn " + r.delegate() + ".findFirst({\n\t\t\twhere: " + r.where("id") + ",\n\t\t})" + r.catch("id") + ";\n\t}"
+	return method{getQuery, getFirstQuery, false}
 }
 
-func createFunc(modelName string) string {
+func (r Resolver) createFunc() method {
+	modelName := r.Model.Name
 	createInputType := "create" + modelName + "Input"
-	createMutation := "\t@Mutation(() => " + modelName + ")\n\tcreate" + modelName + "(@Ctx() { prisma }: context, @Arg(\"input\") input: " + createInputType + "){\n"
-	createQuery := "\t\treturn prisma." + strings.ToLower(modelName) + ".create({\n\t\t\tdata: {\n\t\t\t\t...input\n\t\t\t},\n\t\t});\n\t}"
-	return createMutation + createQuery
+	createMutation := "\t@Mutation(() => " + modelName + ")\n\t" + r.async("create") + "create" + modelName + "(" + r.ctxParam("create") + ", @Arg(\"input\") input: " + createInputType + "){\n"
+	createQuery := r.returns("create", r.delegate()+".create({\n\t\t\tdata: "+r.createData()+",\n\t\t})"+r.catch(""))
+	return method{createMutation, createQuery, r.publishes("create")}
 }
 
-func updateFunc(modelName string) string {
+// createData returns the data of a created record: the input with its
+// nested relation writes, owned by the authenticated user if operations are
+// limited to their owner.
+func (r Resolver) createData() string {
+	entries := append([]string{"...input"}, r.relationData("input", prismaUtil.CreateMode)...)
+	if r.OwnerField != "" {
+		entries = append(entries, r.OwnerField+": "+r.ownerId())
+	}
+	return objectLiteral(entries...)
+}
+
+func (r Resolver) updateFunc() method {
+	modelName := r.Model.Name
 	updateInputType := "update" + modelName + "Input"
+	updateMutation := "\t@Mutation(() => " + modelName + ")\n\t" + r.async("update") + "update" + modelName + "(" + r.ctxParam("update") + ", @Arg(\"input\") input: " + updateInputType + "){\n"
+	// the id selects the record and is never updated
+	entries := append([]string{"..." + r.updateData()}, r.relationData("input", prismaUtil.UpdateMode)...)
+	updateQuery := "\t\tconst { id, ...data } = input;\n" + r.returns("update", r.delegate()+".update({\n\t\t\twhere: "+r.where("id")+",\n\t\t\tdata: "+objectLiteral(entries...)+",\n\t\t})"+r.catchNotFound("id"))
+	return method{updateMutation, updateQuery, r.publishes("update")}
+}
 
-	updateMutation := "\t@Mutation(() => " + modelName + ")\n\tupdate" + modelName + "(@Ctx() { prisma }: context, @Arg(\"input\") input: " + updateInputType + "){\n"
-	updateQuery := "\t\treturn prisma." + strings.ToLower(modelName) + ".update({\n\t\t\twhere:{id: input.id},\n\t\t\tdata: {\n\t\t\t\t...input\n\t\t\t},\n\t\t});\n\t}"
-	return updateMutation + updateQuery
+func (r Resolver) deleteFunc(idParam string) method {
+	modelName := r.Model.Name
+	if r.Model.SoftDelete() {
+		return r.softDeleteFunc(idParam)
+	}
+	deleteMutation := "\t@Mutation(() => " + modelName + ", { nullable: true })\n\t" + r.async("delete") + "delete" + modelName + "(" + r.ctxParam("delete") + ", " + idParam + "){\n"
+	deleteQuery := r.returns("delete", r.delegate()+".delete({\n\t\t\twhere: "+r.where("id")+",\n\t\t})"+r.catchNotFound("id"))
+
+	return method{deleteMutation, deleteQuery, r.publishes("delete")}
 }
 
-func deleteFunc(modelName string, idType string) string {
-	deleteMutation := "\t@Mutation(() => " + modelName + ", { nullable: true })\n\tdelete" + modelName + "(@Ctx() { prisma }: context, @Arg(\"id\") id: " + idType + "){\n"
-	deleteQuery := "\t\treturn prisma." + strings.ToLower(modelName) + ".delete({\n\t\t\twhere: {\n\t\t\t\tid: id\n\t\t\t},\n\t\t});\n\t}"
+func (r Resolver) upsertFunc(idParam string) method {
+	modelName := r.Model.Name
+	createInputType := "create" + modelName + "Input"
+	// an existing record keeps its owner, like in createData
+	entries := append([]string{"...input"}, r.relationData("input", prismaUtil.CreateMode)...)
+	if r.OwnerField != "" {
+		entries = append(entries, r.OwnerField+": "+r.ownerId())
+	}
+	update := objectLiteral(entries...)
+	upsertMutation := "\t@Mutation(() => " + modelName + ")\n\tupsert" + modelName + "(" + r.ctxParam("upsert") + ", " + idParam + ", @Arg(\"input\") input: " + createInputType + "){\n"
+	upsertQuery := "\t\treturn " + r.delegate() + ".upsert({\n\t\t\twhere: " + r.where("id") + ",\n\t\t\tcreate: " + r.createData() + ",\n\t\t\tupdate: " + update + ",\n\t\t})" + r.catchNotFound("id") + ";\n\t}"
+	return method{upsertMutation, upsertQuery, false}
+}
 
-	return deleteMutation + deleteQuery
+func (r Resolver) createManyFunc() method {
+	modelName := r.Model.Name
+	createInputType := "create" + modelName + "Input"
+	data := "inputs"
+	entries := r.withoutRelations()
+	if r.OwnerField != "" {
+		entries = append(entries, r.OwnerField+": "+r.ownerId())
+	}
+	if len(entries) > 0 {
+		data = "inputs.map((input) => ({ ...input, " + strings.Join(entries, ", ") + " }))"
+	}
+	createManyMutation := "\t@Mutation(() => Int)\n\tasync createMany" + plural(modelName) + "(" + r.ctxParam("createMany") + ", @Arg(\"inputs\", () => [" + createInputType + "]) inputs: " + createInputType + "[]){\n"
+	createManyQuery := "\t\tconst { count } = await " + r.delegate() + ".createMany({\n\t\t\tdata: " + data + ",\n\t\t})" + r.catch("") + ";\n\t\treturn count;\n\t}"
+	return method{createManyMutation, createManyQuery, true}
+}
+
+func (r Resolver) updateManyFunc() method {
+	modelName := r.Model.Name
+	updateManyMutation := "\t@Mutation(() => Int)\n\tasync updateMany" + plural(modelName) + "(" + r.ctxParam("updateMany") + ", @Arg(\"where\") where: " + modelName + "WhereInput, @Arg(\"data\") data: updateMany" + modelName + "Input){\n"
+	updateManyQuery := "\t\tconst { count } = await " + r.delegate() + ".updateMany({\n\t\t\twhere: " + r.whereMany() + ",\n\t\t\tdata: " + objectLiteral("..."+r.updateData()) + ",\n\t\t})" + r.catch("") + ";\n\t\treturn count;\n\t}"
+	return method{updateManyMutation, updateManyQuery, true}
+}
+
+func (r Resolver) deleteManyFunc() method {
+	modelName := r.Model.Name
+	if r.Model.SoftDelete() {
+		return r.softDeleteManyFunc()
+	}
+	deleteManyMutation := "\t@Mutation(() => Int)\n\tasync deleteMany" + plural(modelName) + "(" + r.ctxParam("deleteMany") + ", @Arg(\"where\") where: " + modelName + "WhereInput){\n"
+	deleteManyQuery := "\t\tconst { count } = await " + r.delegate() + ".deleteMany({\n\t\t\twhere: " + r.whereMany() + ",\n\t\t})" + r.catch("") + ";\n\t\treturn count;\n\t}"
+	return method{deleteManyMutation, deleteManyQuery, true}
+}
+
+func (r Resolver) countFunc() method {
+	modelName := r.Model.Name
+	countQuery := "\t@Query(() => Int)\n\tcount" + plural(modelName) + "(" + r.ctxParam("count") + ", @Arg(\"where\", { nullable: true }) where?: " + modelName + "WhereInput){\n"
+	countBody := "\t\treturn " + r.delegate() + ".count({\n\t\t\twhere: " + r.whereMany() + ",\n\t\t})" + r.catch("") + ";\n\t}"
+	return method{countQuery, countBody, false}
+}
+
+// whereMany returns the where clause of the bulk operations, built from the
+// where argument.
+func (r Resolver) whereMany() string {
+	return objectLiteral(r.filters("...where")...)
+}
+
+func plural(modelName string) string {
+	return pluralize.NewClient().Plural(modelName)
 }
 
 func FornatTS(lines []string) string {
@@ -118,62 +417,169 @@ func FornatTS(lines []string) string {
 	return ts
 }
 
-func createCtx() {
+// PubSubField declares the PubSub the publishing mutations take from the
+// context. The PubSubs type-graphql 2 accepts, such as createPubSub() from
+// @graphql-yoga/subscription, have this publish method.
+const PubSubField = "pubSub: { publish(topic: string, payload: unknown): unknown };"
+
+// ContextTS is the context.ts the resolvers get the Prisma client from.
+const ContextTS = "import { PrismaClient } from \"@prisma/client\";\nimport { Request, Response } from \"express\";\n" +
+	"export interface context {\n\tprisma: PrismaClient;\n\treq: Request;\n\tres: Response;\n}"
+
+func createCtx() error {
 	pathName := "./src/resolvers/context.ts"
 	if !checkFileExists(pathName) {
 		f, err := os.OpenFile(pathName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		defer f.Close()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
-		imports := "import { PrismaClient } from \"@prisma/client\";\nimport { Request, Response } from \"express\";"
-		ctx := "export interface context {\n\tprisma: PrismaClient;\n\treq: Request;\n\tres: Response;\n}"
-		f.WriteString(imports + "\n" + ctx)
+		defer f.Close()
+		_, err = f.WriteString(ContextTS)
+		return err
+	}
+	return nil
+}
+
+// createScalars writes the scalars genql generates itself (those without a
+// module to import from) if the model uses any of them.
+func createScalars(dir string, model prismaUtil.Model) error {
+	pathName := dir + "scalars.ts"
+	if _, ok := model.TypeImports()[prismaUtil.LocalScalars]; !ok || checkFileExists(pathName) {
+		return nil
 	}
+	f, err := os.OpenFile(pathName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	imports := "import { GraphQLScalarType, Kind } from \"graphql\";"
+	bytes := "export const BytesScalar = new GraphQLScalarType({\n\tname: \"Bytes\",\n\tdescription: \"Binary data encoded as a base64 string\",\n" +
+		"\tserialize: (value) => Buffer.from(value as Uint8Array).toString(\"base64\"),\n" +
+		"\tparseValue: (value) => Buffer.from(value as string, \"base64\"),\n" +
+		"\tparseLiteral: (ast) => (ast.kind === Kind.STRING ? Buffer.from(ast.value, \"base64\") : null),\n});"
+	_, err = f.WriteString(imports + "\n\n" + bytes + "\n")
+	return err
+}
+
+// addCtxField adds a field to the context interface in context.ts, unless
+// the interface already declares it. importLine is added to the top of the
+// file if the field's type needs it.
+func addCtxField(name string, declaration string, importLine string) error {
+	return insertEntry("./src/resolvers/context.ts", name, declaration, importLine)
+}
+
+// insertEntry adds an entry to the last block of a generated file, e.g. the
+// context interface, unless the block already has an entry with that name.
+func insertEntry(pathName string, name string, entry string, importLine string) error {
+	f, err := os.ReadFile(pathName)
+	if err != nil {
+		return err
+	}
+	ts := string(f)
+	if regexp.MustCompile(`(?m)^\s*` + regexp.QuoteMeta(name) + `\??:`).MatchString(ts) {
+		return nil
+	}
+	end := strings.LastIndex(ts, "}")
+	if end == -1 {
+		return fmt.Errorf("block to add %s to not found in %s", name, pathName)
+	}
+	ts = ts[:end] + "\t" + entry + "\n" + ts[end:]
+	if importLine != "" && !strings.Contains(ts, importLine) {
+		ts = importLine + "\n" + ts
+	}
+	return os.WriteFile(pathName, []byte(ts), 0644)
 }
-func getIdType(model *prismaUtil.Model) string {
+
+func getIdField(model *prismaUtil.Model) prismaUtil.Field {
 	for _, f := range model.Fields {
 		if f.Attribute != "" && strings.Index(f.Attribute, "@id") != -1 {
-			typename, ok := prismaUtil.MAPPED_TS[f.Typename]
-			if !ok {
+			if _, ok := prismaUtil.MAPPED_TS[f.Typename]; !ok {
 				fmt.Println("id typename cannot be handled")
 				os.Exit(1)
 			}
-			return typename
+			return f
 		}
 	}
 	panic("id typename cannot be handled")
 }
 
-func addTypes(model prismaUtil.Model) {
-	resolverPath := "./src/resolvers/" + model.Name
+// Check returns why resolvers can't be generated for the model, if they can't.
+func (r Resolver) Check() error {
+	idField, ok := r.Model.IdField()
+	if !ok {
+		return errors.New("no @id field")
+	}
+	if _, ok := prismaUtil.MAPPED_TS[idField.Typename]; !ok {
+		return errors.New("id type cannot be handled")
+	}
+	if _, ok := r.Model.Field(r.OwnerField); r.OwnerField != "" && !ok {
+		return fmt.Errorf("owner field (%s) not found", r.OwnerField)
+	}
+	if r.includesDeleted() && len(r.Roles["includeDeleted"]) == 0 {
+		return errors.New(includeDeletedRoles)
+	}
+	return nil
+}
 
-	filePath := resolverPath + "/types.ts"
+// idArg returns the id parameter of get and delete operations, typed
+// explicitly so Int ids aren't exposed as Float.
+func idArg(idField prismaUtil.Field) string {
+	return "@Arg(\"id\", " + idField.TypeFunc() + ") id: " + prismaUtil.MAPPED_TS[idField.Typename]
+}
+
+func (r Resolver) addTypes() error {
+	filePath := "./src/resolvers/" + r.Model.Name + "/types.ts"
 	if checkFileExists(filePath) {
-		fmt.Printf("file (%s) already exists\n", filePath)
-		os.Exit(1)
+		return fmt.Errorf("file (%s) already exists", filePath)
 	}
+	imports, types := r.types()
+	return writeFile(filePath, prismaUtil.RenderImports(imports)+"\n"+types)
+}
 
-	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	defer f.Close()
-	header := "import { Field, InputType, ObjectType } from \"type-graphql\"\n\n"
-	f.WriteString(header + model.ObjectType() + "\n" + model.CreateInputType() + "\n" + model.UpdateInputType())
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+// types returns the object type and inputs of the model's types.ts, with the
+// names they import keyed by module.
+func (r Resolver) types() (map[string][]string, string) {
+	model := r.Model
+	imports := model.TypeImports()
+	relations := r.relations()
+	prismaUtil.RelationImports(relations, imports)
+	// the object type and update input expose the global id in relay mode
+	relay := model
+	if r.Relay {
+		relay = model.WithGlobalId()
+		for module, names := range relay.TypeImports() {
+			imports[module] = append(imports[module], names...)
+		}
+		imports["../relay"] = []string{"Node"}
+	}
+	if r.Relay && r.has("connection") {
+		imports["../relay"] = append(imports["../relay"], "PageInfo")
+	}
+	types := relay.ObjectType() + "\n"
+	if r.Relay && r.has("connection") {
+		types += r.connectionTypes() + "\n"
+	}
+	// declared before the inputs that reference them
+	if len(relations) > 0 {
+		types += prismaUtil.RelationTypes(relations) + "\n"
 	}
+	types += model.CreateInputType(relations...) + "\n" + relay.UpdateInputType(relations...)
+	if r.HasUpdateManyInput() {
+		types += "\n" + model.UpdateManyInputType()
+	}
+	if r.HasWhereInput() {
+		types += "\n" + model.WhereInputType()
+	}
+	return imports, types
 }
 
-func checkFileExists(filePath string) bool {
-	if _, err := os.Stat(filePath); err == nil {
-		return true
-
-	} else if errors.Is(err, os.ErrNotExist) {
-		return false
-	}
-	fmt.Printf("error ecountered find file @ path: %s\n", filePath)
-	os.Exit(1)
+func writeFile(filePath string, content string) error {
+	return os.WriteFile(filePath, []byte(content), 0644)
+}
 
-	return false
+// checkFileExists reports whether a file exists. Other errors are left to
+// the read or write that follows.
+func checkFileExists(filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
 }
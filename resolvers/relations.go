@@ -0,0 +1,54 @@
+package resolvers
+
+import (
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+)
+
+// relations returns the relations written through the create and update
+// inputs. A relation set from the owner field is left out, since the owner
+// always comes from the authenticated user.
+func (r Resolver) relations() []prismaUtil.Relation {
+	relations := []prismaUtil.Relation{}
+	for _, rel := range r.Relations {
+		owned := false
+		for _, fk := range rel.Field.RelationFields() {
+			owned = owned || fk == r.OwnerField
+		}
+		if !owned {
+			relations = append(relations, rel)
+		}
+	}
+	return relations
+}
+
+// relationData returns the entries mapping the nested relation inputs of
+// input to Prisma's nested writes, e.g.
+// `user: input.user && { connect: input.user.connect }`.
+func (r Resolver) relationData(input string, mode prismaUtil.TypeMode) []string {
+	entries := []string{}
+	for _, rel := range r.relations() {
+		value := input + "." + rel.Field.Name
+		nested := rel.CreateNestedInput()
+		if mode == prismaUtil.UpdateMode {
+			nested = rel.UpdateNestedInput()
+		}
+		writes := []string{}
+		for _, f := range nested.Fields {
+			writes = append(writes, f.Name+": "+value+"."+f.Name)
+		}
+		entries = append(entries, rel.Field.Name+": "+value+" && { "+strings.Join(writes, ", ")+" }")
+	}
+	return entries
+}
+
+// withoutRelations returns the entries clearing the relation inputs, for
+// createMany which doesn't support nested writes.
+func (r Resolver) withoutRelations() []string {
+	entries := []string{}
+	for _, rel := range r.relations() {
+		entries = append(entries, rel.Field.Name+": undefined")
+	}
+	return entries
+}
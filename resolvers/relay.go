@@ -0,0 +1,152 @@
+package resolvers
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+)
+
+// In relay mode the object type implements Node with a global id, id
+// arguments take global ids, and a connection query pages through the
+// model's records.
+
+// createRelay writes the Node interface, connection types and pagination
+// helpers shared by the resolvers generated with --relay.
+func createRelay() error {
+	pathName := "./src/resolvers/relay.ts"
+	if checkFileExists(pathName) {
+		return nil
+	}
+	f, err := os.OpenFile(pathName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	imports := "import { GraphQLError } from \"graphql\";\nimport { ArgsType, Field, ID, Int, InterfaceType, ObjectType } from \"type-graphql\";"
+	types := "@InterfaceType({ resolveType: (value) => value.__typename })\nexport abstract class Node {\n\t@Field(() => ID)\n\tid: string;\n}\n\n" +
+		"@ObjectType()\nexport class PageInfo {\n\t@Field(() => Boolean)\n\thasNextPage: boolean;\n\t@Field(() => Boolean)\n\thasPreviousPage: boolean;\n" +
+		"\t@Field(() => String, { nullable: true })\n\tstartCursor?: string;\n\t@Field(() => String, { nullable: true })\n\tendCursor?: string;\n}\n\n" +
+		"@ArgsType()\nexport class ConnectionArgs {\n\t@Field(() => Int, { nullable: true })\n\tfirst?: number;\n\t@Field(() => String, { nullable: true })\n\tafter?: string;\n" +
+		"\t@Field(() => Int, { nullable: true })\n\tlast?: number;\n\t@Field(() => String, { nullable: true })\n\tbefore?: string;\n}"
+	ids := "const PAGE_SIZE = 20;\nconst MAX_PAGE_SIZE = 100;\n\n" +
+		"const encode = (value: string) => Buffer.from(value).toString(\"base64\");\nconst decode = (value: string) => Buffer.from(value, \"base64\").toString();\n\n" +
+		"// Global ids encode the type name and the record's id as base64 \"Type:id\".\n" +
+		"export const toGlobalId = (type: string, id: string | number | bigint) => encode(`${type}:${id}`);\n\n" +
+		"export const parseGlobalId = (globalId: string) => {\n\tconst decoded = decode(globalId);\n\tconst separator = decoded.indexOf(\":\");\n" +
+		"\treturn { type: decoded.slice(0, separator), id: decoded.slice(separator + 1) };\n};\n\n" +
+		"// Returns the record id of a global id, checking that it belongs to type.\n" +
+		"export const fromGlobalId = (globalId: string, type: string) => {\n\tconst parsed = parseGlobalId(globalId);\n" +
+		"\tif (parsed.type !== type) {\n\t\tthrow new GraphQLError(`invalid ${type} id`, { extensions: { code: \"BAD_USER_INPUT\" } });\n\t}\n\treturn parsed.id;\n};\n\n" +
+		"const toCursor = (id: string | number | bigint) => encode(`cursor:${id}`);\nconst fromCursor = (cursor: string) => decode(cursor).slice(\"cursor:\".length);"
+	paginate := "// Maps first/after and last/before onto Prisma cursor pagination over the\n// records ordered by id. One extra record is fetched to tell whether\n// another page follows.\n" +
+		"export const paginate = async <T extends { id: string | number | bigint }>(\n\targs: ConnectionArgs,\n\tparseId: (id: string) => T[\"id\"],\n" +
+		"\tfindMany: (page: { take: number; skip?: number; cursor?: { id: T[\"id\"] }; orderBy: { id: \"asc\" } }) => Promise<T[]>\n) => {\n" +
+		"\tconst backward = args.last !== undefined && args.last !== null;\n" +
+		"\tconst size = Math.min(Math.max((backward ? args.last : args.first) ?? PAGE_SIZE, 0), MAX_PAGE_SIZE);\n" +
+		"\tconst cursor = backward ? args.before : args.after;\n" +
+		"\tconst records = await findMany({\n\t\ttake: backward ? -(size + 1) : size + 1,\n\t\tskip: cursor ? 1 : undefined,\n" +
+		"\t\tcursor: cursor ? { id: parseId(fromCursor(cursor)) } : undefined,\n\t\torderBy: { id: \"asc\" },\n\t});\n" +
+		"\tconst hasMore = records.length > size;\n" +
+		"\tconst nodes = backward ? records.slice(hasMore ? 1 : 0) : records.slice(0, size);\n" +
+		"\tconst edges = nodes.map((node) => ({ cursor: toCursor(node.id), node }));\n" +
+		"\treturn {\n\t\tedges,\n\t\tpageInfo: {\n\t\t\thasNextPage: backward ? Boolean(cursor) : hasMore,\n\t\t\thasPreviousPage: backward ? hasMore : Boolean(cursor),\n" +
+		"\t\t\tstartCursor: edges[0]?.cursor,\n\t\t\tendCursor: edges[edges.length - 1]?.cursor,\n\t\t},\n\t};\n};"
+	_, err = f.WriteString(imports + "\n\n" + types + "\n\n" + ids + "\n\n" + paginate + "\n")
+	return err
+}
+
+// createNode writes the root node query, which loads a record of any model
+// generated with --relay by its global id.
+func createNode() error {
+	pathName := "./src/resolvers/node.ts"
+	if checkFileExists(pathName) {
+		return nil
+	}
+	f, err := os.OpenFile(pathName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	imports := "import { Arg, Ctx, ID, Query, Resolver } from \"type-graphql\";\nimport { context } from \"./context\";\nimport { Node, parseGlobalId } from \"./relay\";"
+	resolver := "@Resolver()\nexport class NodeResolver {\n\t@Query(() => Node, { nullable: true })\n" +
+		"\tasync node(@Ctx() ctx: context, @Arg(\"id\", () => ID) id: string) {\n" +
+		"\t\tconst { type, id: recordId } = parseGlobalId(id);\n" +
+		"\t\tif (!Object.prototype.hasOwnProperty.call(loaders, type)) {\n\t\t\treturn null;\n\t\t}\n" +
+		"\t\tconst record = await loaders[type](ctx, recordId);\n" +
+		"\t\treturn record && { ...record, __typename: type };\n\t}\n}"
+	loaders := "// Loaders of the models generated with --relay, keyed by type name.\n" +
+		"const loaders: Record<string, (ctx: context, id: string) => Promise<object | null>> = {\n};"
+	_, err = f.WriteString(imports + "\n\n" + resolver + "\n\n" + loaders + "\n")
+	return err
+}
+
+// addNodeLoader registers the model with the node query, applying the same
+// filters and roles as the get operation.
+func (r Resolver) addNodeLoader() error {
+	idField := getIdField(&r.Model)
+	where := strings.Join(r.filters("id: "+parseId(idField)+"(id)"), ", ")
+	query := "prisma." + strings.ToLower(r.Model.Name) + ".findFirst({ where: { " + where + " } })"
+	params := "{ prisma }"
+	if r.Auth {
+		params = "{ prisma, user }"
+		condition := "user"
+		if roles, ok := r.Roles["get"]; ok {
+			condition += " && [\"" + strings.Join(roles, "\", \"") + "\"].some((role) => user.roles.includes(role))"
+		}
+		query = condition + " ? " + query + " : Promise.resolve(null)"
+	} else if r.OwnerField != "" {
+		params = "{ prisma, user }"
+	}
+	return insertEntry("./src/resolvers/node.ts", r.Model.Name, r.Model.Name+": ("+params+", id) => "+query+",", "")
+}
+
+// parseId returns the function converting an id decoded from a global id or
+// cursor to the id field's type. BigInt ids stay exact above 2^53.
+func parseId(idField prismaUtil.Field) string {
+	switch {
+	case idField.Typename == prismaUtil.BigIntType:
+		return "BigInt"
+	case prismaUtil.MAPPED_TS[idField.Typename] == "string":
+		return "String"
+	}
+	return "Number"
+}
+
+// idValue returns the record id of an id argument, decoding it from a global
+// id in relay mode.
+func (r Resolver) idValue(id string) string {
+	if !r.Relay {
+		return id
+	}
+	return parseId(getIdField(&r.Model)) + "(fromGlobalId(" + id + ", \"" + r.Model.Name + "\"))"
+}
+
+// connectionTypes returns the Edge and Connection types of the model.
+func (r Resolver) connectionTypes() string {
+	name := r.Model.Name
+	edge := "@ObjectType()\nexport class " + name + "Edge {\n\t@Field(() => String)\n\tcursor: string\n\t@Field(() => " + name + ")\n\tnode: " + name + "\n}"
+	connection := "@ObjectType()\nexport class " + name + "Connection {\n\t@Field(() => [" + name + "Edge])\n\tedges: " + name + "Edge[]\n\t@Field(() => PageInfo)\n\tpageInfo: PageInfo\n}"
+	return edge + "\n" + connection
+}
+
+func (r Resolver) connectionFunc() string {
+	modelName := r.Model.Name
+	query := "prisma." + strings.ToLower(modelName) + ".findMany(page)"
+	if filters := r.filters(); len(filters) > 0 {
+		query = "prisma." + strings.ToLower(modelName) + ".findMany({ ...page, where: { " + strings.Join(filters, ", ") + " } })"
+	}
+	connectionQuery := "\t@Query(() => " + modelName + "Connection)\n\t" + connectionName(modelName) + "(" + r.ctxParam("connection") + ", @Args() args: ConnectionArgs){\n"
+	connectionBody := "\t\treturn paginate(args, " + parseId(getIdField(&r.Model)) + ", (page) => " + query + ")" + r.catch("") + ";\n\t}"
+	return connectionQuery + connectionBody
+}
+
+func connectionName(modelName string) string {
+	return strings.ToLower(modelName[:1]) + plural(modelName)[1:]
+}
+
+// globalIdFunc resolves the id of the object type to the record's global id.
+func (r Resolver) globalIdFunc() string {
+	idField := getIdField(&r.Model)
+	return "\t@FieldResolver(() => ID)\n\tid(@Root() record: { id: " + prismaUtil.MAPPED_TS[idField.Typename] + " }): string {\n\t\treturn toGlobalId(\"" + r.Model.Name + "\", record.id);\n\t}"
+}
@@ -0,0 +1,132 @@
+package resolvers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Operations are the operations a resolver can be generated with, in the
+// order they are written to the resolver class. restore and includeDeleted
+// are only generated for soft-deleted models, connection only in relay mode.
+var Operations = []string{"get", "create", "update", "delete", "upsert", "createMany", "updateMany", "deleteMany", "count", "restore", "includeDeleted", "connection"}
+
+// DefaultOperations are generated unless --Only picks the operations. upsert,
+// createMany, updateMany, deleteMany and count are only generated when picked,
+// and includeDeleted when picked or given roles.
+var DefaultOperations = []string{"get", "create", "update", "delete", "restore", "connection"}
+
+// SelectOperations returns the operations to generate, in the order of
+// Operations: the ones in only, or the defaults if only is empty, without
+// the ones in except. includeDeleted returns deleted records, so it is
+// only selected with the roles allowed to call it.
+func SelectOperations(except []string, only []string, roles map[string][]string) ([]string, error) {
+	for _, val := range append(append([]string{}, except...), only...) {
+		if !IsOperation(val) {
+			return nil, fmt.Errorf("unknown operation (%s)", val)
+		}
+	}
+	if len(only) == 0 {
+		only = DefaultOperations
+		if len(roles["includeDeleted"]) > 0 {
+			only = append(append([]string{}, only...), "includeDeleted")
+		}
+	}
+	include := []string{}
+	for _, op := range Operations {
+		if contains(only, op) && !contains(except, op) {
+			include = append(include, op)
+		}
+	}
+	if contains(include, "includeDeleted") && len(roles["includeDeleted"]) == 0 {
+		return nil, errors.New(includeDeletedRoles)
+	}
+	return include, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func IsOperation(name string) bool {
+	for _, op := range Operations {
+		if op == name {
+			return true
+		}
+	}
+	return false
+}
+
+type Arg struct {
+	Name string
+	Type string // GraphQL type, e.g. "String!"
+}
+
+// Signature describes the GraphQL field a resolver operation adds to the
+// Query, Mutation or Subscription type.
+type Signature struct {
+	Kind    string // "Query", "Mutation" or "Subscription"
+	Name    string
+	Args    []Arg
+	Returns string
+}
+
+func (r Resolver) Signatures() []Signature {
+	name := r.Model.Name
+	idArg := Arg{Name: "id"}
+	model := r.Model
+	if r.Relay {
+		model = model.WithGlobalId()
+	}
+	if idField, ok := model.IdField(); ok {
+		idArg.Type = idField.GraphQLType() + "!"
+	}
+
+	signatures := []Signature{}
+	for _, val := range r.Functions {
+		switch val {
+		case "get":
+			signatures = append(signatures, Signature{"Query", "get" + name, []Arg{idArg}, name})
+		case "create":
+			signatures = append(signatures, Signature{"Mutation", "create" + name, []Arg{{"input", "create" + name + "Input!"}}, name + "!"})
+		case "update":
+			signatures = append(signatures, Signature{"Mutation", "update" + name, []Arg{{"input", "update" + name + "Input!"}}, name + "!"})
+		case "delete":
+			signatures = append(signatures, Signature{"Mutation", "delete" + name, []Arg{idArg}, name})
+		case "upsert":
+			signatures = append(signatures, Signature{"Mutation", "upsert" + name, []Arg{idArg, {"input", "create" + name + "Input!"}}, name + "!"})
+		case "createMany":
+			signatures = append(signatures, Signature{"Mutation", "createMany" + plural(name), []Arg{{"inputs", "[create" + name + "Input!]!"}}, "Int!"})
+		case "updateMany":
+			signatures = append(signatures, Signature{"Mutation", "updateMany" + plural(name), []Arg{{"where", name + "WhereInput!"}, {"data", "updateMany" + name + "Input!"}}, "Int!"})
+		case "deleteMany":
+			signatures = append(signatures, Signature{"Mutation", "deleteMany" + plural(name), []Arg{{"where", name + "WhereInput!"}}, "Int!"})
+		case "count":
+			signatures = append(signatures, Signature{"Query", "count" + plural(name), []Arg{{"where", name + "WhereInput"}}, "Int!"})
+		case "restore":
+			if r.Model.SoftDelete() {
+				signatures = append(signatures, Signature{"Mutation", "restore" + name, []Arg{idArg}, name})
+			}
+		case "connection":
+			if r.Relay {
+				args := []Arg{{"first", "Int"}, {"after", "String"}, {"last", "Int"}, {"before", "String"}}
+				signatures = append(signatures, Signature{"Query", connectionName(name), args, name + "Connection!"})
+			}
+		case "includeDeleted":
+			if r.includesDeleted() {
+				signatures = append(signatures, Signature{"Query", "include" + name + "Deleted", []Arg{idArg}, name})
+			}
+		}
+	}
+	for _, e := range events {
+		if r.Subscriptions && r.has(e.Operation) {
+			signatures = append(signatures, Signature{"Subscription", subscriptionName(name, e.Event), []Arg{{"id", strings.TrimSuffix(idArg.Type, "!")}}, name + "!"})
+		}
+	}
+	return signatures
+}
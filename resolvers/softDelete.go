@@ -0,0 +1,54 @@
+package resolvers
+
+import "github.com/tk04/genql/prismaUtil"
+
+// Models with a deletedAt field are soft-deleted: delete operations set the
+// timestamp, and every other operation skips records that have it set.
+
+func (r Resolver) softDeleteFunc(idParam string) method {
+	modelName := r.Model.Name
+	deleteMutation := "\t@Mutation(() => " + modelName + ", { nullable: true })\n\t" + r.async("delete") + "delete" + modelName + "(" + r.ctxParam("delete") + ", " + idParam + "){\n"
+	deleteQuery := r.returns("delete", r.delegate()+".update({\n\t\t\twhere: "+r.where("id")+",\n\t\t\tdata: {\n\t\t\t\t"+prismaUtil.SoftDeleteField+": new Date()\n\t\t\t},\n\t\t})"+r.catchNotFound("id"))
+
+	return method{deleteMutation, deleteQuery, r.publishes("delete")}
+}
+
+func (r Resolver) softDeleteManyFunc() method {
+	modelName := r.Model.Name
+	deleteManyMutation := "\t@Mutation(() => Int)\n\tasync deleteMany" + plural(modelName) + "(" + r.ctxParam("deleteMany") + ", @Arg(\"where\") where: " + modelName + "WhereInput){\n"
+	deleteManyQuery := "\t\tconst { count } = await " + r.delegate() + ".updateMany({\n\t\t\twhere: " + r.whereMany() + ",\n\t\t\tdata: {\n\t\t\t\t" + prismaUtil.SoftDeleteField + ": new Date()\n\t\t\t},\n\t\t})" + r.catch("") + ";\n\t\treturn count;\n\t}"
+	return method{deleteManyMutation, deleteManyQuery, true}
+}
+
+func (r Resolver) restoreFunc(idParam string) method {
+	modelName := r.Model.Name
+	restoreMutation := "\t@Mutation(() => " + modelName + ", { nullable: true })\n\trestore" + modelName + "(" + r.ctxParam("restore") + ", " + idParam + "){\n"
+	restoreQuery := "\t\treturn " + r.delegate() + ".update({\n\t\t\twhere: " + r.whereIncludingDeleted("id") + ",\n\t\t\tdata: {\n\t\t\t\t" + prismaUtil.SoftDeleteField + ": null\n\t\t\t},\n\t\t})" + r.catchNotFound("id") + ";\n\t}"
+	return method{restoreMutation, restoreQuery, false}
+}
+
+const includeDeletedRoles = "includeDeleted returns deleted records, set the roles allowed to call it with --roles includeDeleted=ROLE"
+
+// includesDeleted reports whether the includeDeleted query is generated.
+// Check rejects it without the roles allowed to call it.
+func (r Resolver) includesDeleted() bool {
+	return r.Model.SoftDelete() && r.has("includeDeleted")
+}
+
+// includeDeletedFunc returns a record by id whether or not it was deleted.
+func (r Resolver) includeDeletedFunc(idParam string) method {
+	modelName := r.Model.Name
+	getQuery := "\t@Query(() => " + modelName + ", { nullable: true })\n\tinclude" + modelName + "Deleted(" + r.ctxParam("includeDeleted") + ", " + idParam + "){\n"
+	getFirstQuery := "\t\treturn " + r.delegate() + ".findFirst({\n\t\t\twhere: " + r.whereIncludingDeleted("id") + ",\n\t\t})" + r.catch("id") + ";\n\t}"
+	return method{getQuery, getFirstQuery, false}
+}
+
+func (r Resolver) whereIncludingDeleted(id string) string {
+	entries := []string{}
+	for _, entry := range r.filters("id: " + r.idValue(id)) {
+		if entry != prismaUtil.SoftDeleteField+": null" {
+			entries = append(entries, entry)
+		}
+	}
+	return objectLiteral(entries...)
+}
@@ -0,0 +1,88 @@
+package resolvers
+
+import (
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+)
+
+// events are the mutations that publish to a model's topics, and the event
+// each one publishes.
+var events = []struct{ Operation, Event string }{
+	{"create", "created"},
+	{"update", "updated"},
+	{"delete", "deleted"},
+}
+
+func event(operation string) (string, bool) {
+	for _, e := range events {
+		if e.Operation == operation {
+			return e.Event, true
+		}
+	}
+	return "", false
+}
+
+func (r Resolver) topicsName() string {
+	return r.Model.Name + "Topics"
+}
+
+// topics returns the constant holding the PubSub topics of the model, e.g.
+// PostTopics.created = "POST_CREATED".
+func (r Resolver) topics() string {
+	entries := []string{}
+	for _, e := range events {
+		if r.has(e.Operation) {
+			entries = append(entries, e.Event+": \""+strings.ToUpper(r.Model.Name+"_"+e.Event)+"\"")
+		}
+	}
+	return "export const " + r.topicsName() + " = {\n\t" + strings.Join(entries, ",\n\t") + ",\n};\n\n"
+}
+
+// publishes reports whether a mutation publishes the record it returns to
+// the topic of its event.
+func (r Resolver) publishes(operation string) bool {
+	_, ok := event(operation)
+	return r.Subscriptions && ok
+}
+
+func (r Resolver) async(operation string) string {
+	if r.publishes(operation) {
+		return "async "
+	}
+	return ""
+}
+
+// returns returns the end of an operation's body, returning the result of
+// the Prisma call, or publishing it first if the operation publishes.
+func (r Resolver) returns(operation string, call string) string {
+	if !r.publishes(operation) {
+		return "\t\treturn " + call + ";\n\t}"
+	}
+	e, _ := event(operation)
+	return "\t\tconst record = await " + call + ";\n\t\tawait pubSub.publish(" + r.topicsName() + "." + e + ", record);\n\t\treturn record;\n\t}"
+}
+
+// subscriptionFunc returns the subscription to an event, optionally filtered
+// by the id of the record.
+func (r Resolver) subscriptionFunc(e string, idField prismaUtil.Field) string {
+	modelName := r.Model.Name
+	params := "payload, args"
+	payloadId := "payload.id"
+	if r.Relay {
+		payloadId = "toGlobalId(\"" + modelName + "\", payload.id)"
+	}
+	filters := []string{"(args.id === undefined || args.id === null || " + payloadId + " === args.id)"}
+	if r.OwnerField != "" {
+		params += ", context"
+		filters = append(filters, "payload."+r.OwnerField+" === context.user?.id")
+	}
+	subscription := "\t@Subscription(() => " + modelName + ", {\n\t\ttopics: " + r.topicsName() + "." + e + ",\n" +
+		"\t\tfilter: ({ " + params + " }: ResolverFilterData<" + modelName + ", { id?: " + prismaUtil.MAPPED_TS[idField.Typename] + " | null }, context>) =>\n\t\t\t" + strings.Join(filters, " && ") + ",\n\t})\n"
+	handler := "\t" + subscriptionName(modelName, e) + "(@Root() record: " + modelName + ", @Arg(\"id\", " + idField.TypeFunc() + ", { nullable: true }) id?: " + prismaUtil.MAPPED_TS[idField.Typename] + "): " + modelName + " {\n\t\treturn record;\n\t}"
+	return subscription + handler
+}
+
+func subscriptionName(modelName string, e string) string {
+	return strings.ToLower(modelName[:1]) + modelName[1:] + strings.ToUpper(e[:1]) + e[1:]
+}
@@ -0,0 +1,191 @@
+package rest
+
+import (
+	"strconv"
+
+	pluralize "github.com/gertd/go-pluralize"
+	"github.com/tk04/genql/prismaUtil"
+)
+
+// Document is an OpenAPI document, kept as generic JSON so the routes of
+// several models can be merged into one file.
+type Document map[string]any
+
+// NewDocument returns an empty OpenAPI 3.1 document.
+func NewDocument() Document {
+	return Document{
+		"openapi": "3.1.0",
+		"info":    map[string]any{"title": "API", "version": "1.0.0"},
+		"paths":   map[string]any{},
+		"components": map[string]any{
+			"schemas": map[string]any{},
+		},
+	}
+}
+
+// AddModel adds the routes of the model's router and the schemas they use,
+// replacing those of an earlier run.
+func (d Document) AddModel(m prismaUtil.Model, schema prismaUtil.Schema) {
+	paths := d.object("paths")
+	components := d.object("components")
+	schemas, ok := components["schemas"].(map[string]any)
+	if !ok {
+		schemas = map[string]any{}
+		components["schemas"] = schemas
+	}
+	schemas[m.Name] = objectSchema(m, prismaUtil.ObjectMode, schema)
+	schemas[m.Name+"CreateInput"] = objectSchema(m, prismaUtil.CreateMode, schema)
+	schemas[m.Name+"UpdateInput"] = objectSchema(m, prismaUtil.UpdateManyMode, schema)
+
+	idField, _ := m.IdField()
+	ref := func(name string) map[string]any {
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	body := func(name string) map[string]any {
+		return map[string]any{"required": true, "content": map[string]any{"application/json": map[string]any{"schema": ref(name)}}}
+	}
+	response := func(description string, schema map[string]any) map[string]any {
+		if schema == nil {
+			return map[string]any{"description": description}
+		}
+		return map[string]any{"description": description, "content": map[string]any{"application/json": map[string]any{"schema": schema}}}
+	}
+	idParam := map[string]any{"name": "id", "in": "path", "required": true, "schema": fieldSchema(idField, schema)}
+	query := func(name string, description string) map[string]any {
+		return map[string]any{"name": name, "in": "query", "required": false, "description": description, "schema": map[string]any{"type": "integer", "minimum": 0}}
+	}
+	notFound := response("Not found", nil)
+	conflict := response("Unique or foreign key constraint violated", nil)
+	invalid := response("Invalid request body", nil)
+
+	paths[Path(m)] = map[string]any{
+		"get": map[string]any{
+			"operationId": "list" + pluralName(m),
+			"parameters":  []any{query("take", "Number of records, at most 100"), query("skip", "Number of records to skip")},
+			"responses":   map[string]any{"200": response("The records ordered by id", map[string]any{"type": "array", "items": ref(m.Name)}), "400": response("Invalid take or skip", nil)},
+		},
+		"post": map[string]any{
+			"operationId": "create" + m.Name,
+			"requestBody": body(m.Name + "CreateInput"),
+			"responses":   map[string]any{"201": response("The created record", ref(m.Name)), "400": invalid, "409": conflict},
+		},
+	}
+	paths[Path(m)+"/{id}"] = map[string]any{
+		"parameters": []any{idParam},
+		"get": map[string]any{
+			"operationId": "get" + m.Name,
+			"responses":   map[string]any{"200": response("The record", ref(m.Name)), "404": notFound},
+		},
+		"patch": map[string]any{
+			"operationId": "update" + m.Name,
+			"requestBody": body(m.Name + "UpdateInput"),
+			"responses":   map[string]any{"200": response("The updated record", ref(m.Name)), "400": invalid, "404": notFound, "409": conflict},
+		},
+		"delete": map[string]any{
+			"operationId": "delete" + m.Name,
+			"responses":   map[string]any{"204": response("Deleted", nil), "404": notFound},
+		},
+	}
+}
+
+func (d Document) object(key string) map[string]any {
+	value, ok := d[key].(map[string]any)
+	if !ok {
+		value = map[string]any{}
+		d[key] = value
+	}
+	return value
+}
+
+func objectSchema(m prismaUtil.Model, mode prismaUtil.TypeMode, schema prismaUtil.Schema) map[string]any {
+	properties := map[string]any{}
+	required := []any{}
+	for _, f := range restFields(m, mode, schema) {
+		properties[f.Name] = fieldSchema(f, schema)
+		// responses always include every selected field
+		if mode == prismaUtil.ObjectMode || !f.Nullable(mode) {
+			required = append(required, f.Name)
+		}
+	}
+	object := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
+
+// fieldSchema returns the JSON schema of a field as the router encodes it:
+// BigInt as a string, Bytes as base64 and optional columns as nullable.
+func fieldSchema(f prismaUtil.Field, schema prismaUtil.Schema) map[string]any {
+	s := map[string]any{}
+	switch f.Typename {
+	case prismaUtil.StringType:
+		s["type"] = "string"
+	case prismaUtil.IntType:
+		s["type"] = "integer"
+	case prismaUtil.FloatType:
+		s["type"] = "number"
+	case prismaUtil.BooleanType:
+		s["type"] = "boolean"
+	case prismaUtil.DateTimeType:
+		s["type"], s["format"] = "string", "date-time"
+	case prismaUtil.BigIntType:
+		s["type"], s["format"] = "string", "int64"
+	case prismaUtil.BytesType:
+		s["type"], s["contentEncoding"] = "string", "base64"
+	case prismaUtil.JsonType:
+		// any JSON value
+	case prismaUtil.NPType:
+		if e, ok := schema.Enum(f.NPType); ok {
+			values := []any{}
+			for _, v := range e.Values {
+				values = append(values, v.Name)
+			}
+			s["type"], s["enum"] = "string", values
+		}
+	}
+	if _, ok := f.Annotation("email"); ok {
+		s["format"] = "email"
+	}
+	if _, ok := f.Annotation("url"); ok {
+		s["format"] = "uri"
+	}
+	if _, ok := f.Annotation("uuid"); ok {
+		s["format"] = "uuid"
+	}
+	limit := func(key string, annotation string) {
+		if arg, ok := f.Limit(annotation); ok {
+			if n, err := strconv.Atoi(arg); err == nil {
+				s[key] = n
+			}
+		}
+	}
+	if f.Typename == prismaUtil.StringType {
+		limit("minLength", "min")
+		limit("maxLength", "max")
+		if length, ok := f.NativeLength(); ok {
+			if _, annotated := s["maxLength"]; !annotated {
+				n, _ := strconv.Atoi(length)
+				s["maxLength"] = n
+			}
+		}
+	} else {
+		limit("minimum", "min")
+		limit("maximum", "max")
+	}
+	if f.IsArray {
+		s = map[string]any{"type": "array", "items": s}
+	}
+	if f.IsOptional {
+		if t, ok := s["type"].(string); ok {
+			s["type"] = []any{t, "null"}
+		}
+	}
+	return s
+}
+
+// pluralName returns the plural of the model's name in its own casing, e.g.
+// BlogPosts, unlike the lowercase Path.
+func pluralName(m prismaUtil.Model) string {
+	return pluralize.NewClient().Plural(m.Name)
+}
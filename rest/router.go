@@ -0,0 +1,170 @@
+package rest
+
+import (
+	"strings"
+
+	pluralize "github.com/gertd/go-pluralize"
+	"github.com/tk04/genql/prismaUtil"
+)
+
+// Path returns the collection path of the model's routes, e.g. /friends.
+func Path(m prismaUtil.Model) string {
+	return "/" + strings.ToLower(pluralize.NewClient().Plural(m.Name))
+}
+
+// Router returns an Express router with list, get, create, update and delete
+// routes for the model. Request bodies are limited to the fields of the
+// create and update inputs, and responses to the fields of the object type.
+func Router(m prismaUtil.Model, schema prismaUtil.Schema) string {
+	path := Path(m)
+	delegate := "prisma." + strings.ToLower(m.Name)
+	idField, _ := m.IdField()
+	parseId := "req.params.id"
+	if idField.Typename == prismaUtil.BigIntType {
+		parseId = "(toBigInt(req.params.id) as bigint)"
+	} else if prismaUtil.MAPPED_TS[idField.Typename] == "number" {
+		parseId = "Number(req.params.id)"
+	}
+	where := "{ id: " + parseId + " }"
+	listWhere := ""
+	if m.SoftDelete() {
+		where = "{ id: " + parseId + ", " + prismaUtil.SoftDeleteField + ": null }"
+		listWhere = "where: { " + prismaUtil.SoftDeleteField + ": null }, "
+	}
+
+	imports := "import { Prisma, PrismaClient } from \"@prisma/client\";\nimport { NextFunction, Request, Response, Router } from \"express\";"
+	consts := "const select = " + selection(m, schema) + ";\n" +
+		"const createFields = " + fieldList(m, prismaUtil.CreateMode, schema) + ";\n" +
+		"const updateFields = " + fieldList(m, prismaUtil.UpdateManyMode, schema) + ";"
+	decoded, used := decoders(m, schema)
+	if idField.Typename == prismaUtil.BigIntType {
+		used[prismaUtil.BigIntType] = true
+	}
+	helpers := ""
+	if len(used) > 0 {
+		helpers += "// Decode the JSON encoding of BigInt, Bytes and DateTime values. Invalid\n// values are passed on as they are, so Prisma rejects them with a 400.\n"
+		for _, t := range []prismaUtil.PrismaType{prismaUtil.BigIntType, prismaUtil.BytesType, prismaUtil.DateTimeType} {
+			if used[t] {
+				helpers += decoderFuncs[t] + "\n"
+			}
+		}
+		helpers += "\n"
+	}
+	if len(decoded) > 0 {
+		helpers += "const decoders: Record<string, (value: unknown) => unknown> = { " + strings.Join(decoded, ", ") + " };\n\n" +
+			"const pick = (body: Record<string, unknown>, fields: string[]) =>\n" +
+			"\tObject.fromEntries(\n\t\tObject.entries(body ?? {})\n\t\t\t.filter(([key]) => fields.includes(key))\n" +
+			"\t\t\t.map(([key, value]): [string, unknown] => [key, value === null || !decoders[key] ? value : decoders[key](value)])\n\t);\n\n"
+	} else {
+		helpers += "const pick = (body: Record<string, unknown>, fields: string[]) =>\n" +
+			"\tObject.fromEntries(Object.entries(body ?? {}).filter(([key]) => fields.includes(key)));\n\n"
+	}
+	helpers += "// Parses a non-negative integer query parameter, undefined if it is invalid\n" +
+		"const count = (value: unknown, fallback: number) => {\n\tif (value === undefined) {\n\t\treturn fallback;\n\t}\n" +
+		"\treturn typeof value === \"string\" && /^\\d+$/.test(value) ? Number(value) : undefined;\n};\n\n" +
+		"// JSON.stringify can't encode bigint, and encodes Buffers as byte arrays\n" +
+		"const send = (res: Response, status: number, body: unknown) =>\n" +
+		"\tres.status(status).type(\"json\").send(\n" +
+		"\t\tJSON.stringify(body, function (key, value) {\n" +
+		"\t\t\tconst raw = this[key];\n" +
+		"\t\t\tif (Buffer.isBuffer(raw)) {\n\t\t\t\treturn raw.toString(\"base64\");\n\t\t\t}\n" +
+		"\t\t\treturn typeof value === \"bigint\" ? value.toString() : value;\n\t\t})\n\t);\n\n" +
+		"// Maps Prisma errors to HTTP status codes\n" +
+		"const handle = (res: Response, next: NextFunction) => (error: unknown) => {\n" +
+		"\tif (error instanceof Prisma.PrismaClientValidationError) {\n\t\treturn send(res, 400, { error: \"invalid request body\" });\n\t}\n" +
+		"\tif (error instanceof Prisma.PrismaClientKnownRequestError) {\n\t\tswitch (error.code) {\n" +
+		"\t\t\tcase \"P2025\":\n\t\t\t\treturn send(res, 404, { error: \"not found\" });\n" +
+		"\t\t\tcase \"P2002\":\n\t\t\tcase \"P2003\":\n\t\t\t\treturn send(res, 409, { error: error.message, code: error.code, meta: error.meta });\n" +
+		"\t\t}\n\t}\n\tnext(error);\n};"
+	routes := []string{
+		"\trouter.get(\"" + path + "\", (req: Request, res: Response, next: NextFunction) => {\n" +
+			"\t\tconst take = count(req.query.take, 20);\n\t\tconst skip = count(req.query.skip, 0);\n" +
+			"\t\tif (take === undefined || skip === undefined) {\n\t\t\treturn send(res, 400, { error: \"take and skip must be non-negative integers\" });\n\t\t}\n" +
+			"\t\t" + delegate + "\n\t\t\t.findMany({ " + listWhere + "select, take: Math.min(take, 100), skip, orderBy: { id: \"asc\" } })\n" +
+			"\t\t\t.then((records) => send(res, 200, records))\n\t\t\t.catch(handle(res, next));\n\t});",
+		"\trouter.get(\"" + path + "/:id\", (req: Request, res: Response, next: NextFunction) => {\n" +
+			"\t\t" + delegate + "\n\t\t\t.findFirst({ where: " + where + ", select })\n" +
+			"\t\t\t.then((record) => (record ? send(res, 200, record) : send(res, 404, { error: \"not found\" })))\n\t\t\t.catch(handle(res, next));\n\t});",
+		"\trouter.post(\"" + path + "\", (req: Request, res: Response, next: NextFunction) => {\n" +
+			"\t\t" + delegate + "\n\t\t\t.create({ data: pick(req.body, createFields) as Prisma." + m.Name + "UncheckedCreateInput, select })\n" +
+			"\t\t\t.then((record) => send(res, 201, record))\n\t\t\t.catch(handle(res, next));\n\t});",
+		"\trouter.patch(\"" + path + "/:id\", (req: Request, res: Response, next: NextFunction) => {\n" +
+			"\t\t" + delegate + "\n\t\t\t.update({ where: " + where + ", data: pick(req.body, updateFields) as Prisma." + m.Name + "UncheckedUpdateInput, select })\n" +
+			"\t\t\t.then((record) => send(res, 200, record))\n\t\t\t.catch(handle(res, next));\n\t});",
+	}
+	if m.SoftDelete() {
+		routes = append(routes, "\trouter.delete(\""+path+"/:id\", (req: Request, res: Response, next: NextFunction) => {\n"+
+			"\t\t"+delegate+"\n\t\t\t.update({ where: "+where+", data: { "+prismaUtil.SoftDeleteField+": new Date() } })\n"+
+			"\t\t\t.then(() => res.status(204).end())\n\t\t\t.catch(handle(res, next));\n\t});")
+	} else {
+		routes = append(routes, "\trouter.delete(\""+path+"/:id\", (req: Request, res: Response, next: NextFunction) => {\n"+
+			"\t\t"+delegate+"\n\t\t\t.delete({ where: "+where+" })\n"+
+			"\t\t\t.then(() => res.status(204).end())\n\t\t\t.catch(handle(res, next));\n\t});")
+	}
+	router := "export const " + strings.ToLower(m.Name[:1]) + m.Name[1:] + "Router = (prisma: PrismaClient) => {\n\tconst router = Router();\n\n" +
+		strings.Join(routes, "\n\n") + "\n\n\treturn router;\n};"
+	return imports + "\n\n" + consts + "\n\n" + helpers + "\n\n" + router + "\n"
+}
+
+var decoderFuncs = map[prismaUtil.PrismaType]string{
+	prismaUtil.BigIntType:   "const toBigInt = (value: unknown) => {\n\ttry {\n\t\treturn BigInt(value as string);\n\t} catch {\n\t\treturn value;\n\t}\n};",
+	prismaUtil.BytesType:    "const toBuffer = (value: unknown) => (typeof value === \"string\" ? Buffer.from(value, \"base64\") : value);",
+	prismaUtil.DateTimeType: "const toDate = (value: unknown) => (typeof value === \"string\" || typeof value === \"number\" ? new Date(value) : value);",
+}
+
+var decoderNames = map[prismaUtil.PrismaType]string{
+	prismaUtil.BigIntType:   "toBigInt",
+	prismaUtil.BytesType:    "toBuffer",
+	prismaUtil.DateTimeType: "toDate",
+}
+
+// decoders returns the entries of the decoders map, decoding the body fields
+// whose JSON encoding differs from the value Prisma expects, and the types
+// they decode.
+func decoders(m prismaUtil.Model, schema prismaUtil.Schema) ([]string, map[prismaUtil.PrismaType]bool) {
+	entries := []string{}
+	used := map[prismaUtil.PrismaType]bool{}
+	for _, f := range m.Fields {
+		name, ok := decoderNames[f.Typename]
+		if !ok || !(f.InMode(prismaUtil.CreateMode) || f.InMode(prismaUtil.UpdateManyMode)) {
+			continue
+		}
+		used[f.Typename] = true
+		if f.IsArray {
+			name = "(value) => (Array.isArray(value) ? value.map(" + name + ") : value)"
+		}
+		entries = append(entries, f.Name+": "+name)
+	}
+	return entries, used
+}
+
+// restFields returns the fields of the model in the given mode, including
+// enums, which the JSON bodies carry as strings.
+func restFields(m prismaUtil.Model, mode prismaUtil.TypeMode, schema prismaUtil.Schema) []prismaUtil.Field {
+	fields := []prismaUtil.Field{}
+	for _, f := range m.Fields {
+		if f.Typename == prismaUtil.NPType && !schema.IsEnum(f.NPType) {
+			continue
+		}
+		if f.InMode(mode) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func selection(m prismaUtil.Model, schema prismaUtil.Schema) string {
+	entries := []string{}
+	for _, f := range restFields(m, prismaUtil.ObjectMode, schema) {
+		entries = append(entries, f.Name+": true")
+	}
+	return "{ " + strings.Join(entries, ", ") + " }"
+}
+
+func fieldList(m prismaUtil.Model, mode prismaUtil.TypeMode, schema prismaUtil.Schema) string {
+	names := []string{}
+	for _, f := range restFields(m, mode, schema) {
+		names = append(names, "\""+f.Name+"\"")
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
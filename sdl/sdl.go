@@ -0,0 +1,152 @@
+package sdl
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/resolvers"
+)
+
+// Render builds a GraphQL SDL document for every model in the schema, with
+// the root fields and inputs of the given operations. Types, enums and root
+// fields are sorted by name so the output is stable.
+func Render(schema prismaUtil.Schema, functions []string, roles map[string][]string) string {
+	models := append([]prismaUtil.Model{}, schema.Models...)
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	enums := append([]prismaUtil.Enum{}, schema.Enums...)
+	sort.Slice(enums, func(i, j int) bool { return enums[i].Name < enums[j].Name })
+
+	scalars := map[string]struct{}{}
+	types := []string{}
+	roots := map[string][]resolvers.Signature{}
+	for _, m := range models {
+		types = append(types, renderType("type", m.Name, m, prismaUtil.ObjectMode, schema, scalars))
+		if _, ok := m.IdField(); !ok {
+			continue
+		}
+		relations := schema.Relations(m)
+		for _, rel := range relations {
+			unique := rel.UniqueModel()
+			types = append(types, renderType("input", unique.Name, unique, prismaUtil.UpdateMode, schema, scalars))
+			if create := rel.CreateModel(); rel.Creatable() {
+				types = append(types, renderType("input", create.Name, create, prismaUtil.CreateMode, schema, scalars))
+			}
+			types = append(types, renderRelationInput(rel.CreateNestedInput()), renderRelationInput(rel.UpdateNestedInput()))
+		}
+		types = append(types, renderType("input", "create"+m.Name+"Input", m, prismaUtil.CreateMode, schema, scalars, relations...))
+		types = append(types, renderType("input", "update"+m.Name+"Input", m, prismaUtil.UpdateMode, schema, scalars, relations...))
+		resolver := resolvers.Resolver{Model: m, Functions: functions, Roles: roles}
+		if resolver.HasUpdateManyInput() {
+			types = append(types, renderType("input", "updateMany"+m.Name+"Input", m, prismaUtil.UpdateManyMode, schema, scalars))
+		}
+		if resolver.HasWhereInput() {
+			types = append(types, renderType("input", m.Name+"WhereInput", m, prismaUtil.WhereMode, schema, scalars))
+		}
+
+		for _, sig := range resolver.Signatures() {
+			roots[sig.Kind] = append(roots[sig.Kind], sig)
+		}
+	}
+
+	blocks := []string{}
+	for _, name := range sortedKeys(scalars) {
+		blocks = append(blocks, "scalar "+name)
+	}
+	for _, e := range enums {
+		blocks = append(blocks, renderEnum(e))
+	}
+	blocks = append(blocks, types...)
+	for _, kind := range []string{"Query", "Mutation", "Subscription"} {
+		if len(roots[kind]) > 0 {
+			blocks = append(blocks, renderRoot(kind, roots[kind]))
+		}
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+func renderType(keyword string, name string, m prismaUtil.Model, mode prismaUtil.TypeMode, schema prismaUtil.Schema, scalars map[string]struct{}, relations ...prismaUtil.Relation) string {
+	sdl := keyword + " " + name + " {\n"
+	fks := prismaUtil.ForeignKeys(relations)
+	for _, f := range m.Fields {
+		// relations are not part of the generated types
+		if f.Typename == prismaUtil.NPType && !schema.IsEnum(f.NPType) {
+			continue
+		}
+		if !f.InMode(mode) {
+			continue
+		}
+		// foreign keys can be set through the nested relation instead
+		if _, ok := fks[f.Name]; ok && mode == prismaUtil.CreateMode {
+			f.IsOptional = true
+		}
+		if f.Typename != prismaUtil.NPType && isScalar(f.GraphQLType()) {
+			scalars[f.GraphQLType()] = struct{}{}
+		}
+
+		typename := f.GraphQLType()
+		if f.IsArray {
+			typename = "[" + typename + "!]"
+		}
+		if !f.Nullable(mode) {
+			typename += "!"
+		}
+		sdl += "  " + f.Name + ": " + typename + "\n"
+	}
+	for _, rel := range relations {
+		sdl += "  " + rel.Field.Name + ": " + rel.NestedInputName(mode) + "\n"
+	}
+	return sdl + "}"
+}
+
+func renderRelationInput(in prismaUtil.RelationInput) string {
+	sdl := "input " + in.Name + " {\n"
+	for _, f := range in.Fields {
+		typename := f.Type
+		if f.IsList {
+			typename = "[" + typename + "!]"
+		}
+		sdl += "  " + f.Name + ": " + typename + "\n"
+	}
+	return sdl + "}"
+}
+
+func renderEnum(e prismaUtil.Enum) string {
+	sdl := "enum " + e.Name + " {\n"
+	for _, v := range e.Values {
+		sdl += "  " + v.Name + "\n"
+	}
+	return sdl + "}"
+}
+
+func renderRoot(kind string, signatures []resolvers.Signature) string {
+	sort.Slice(signatures, func(i, j int) bool { return signatures[i].Name < signatures[j].Name })
+	sdl := "type " + kind + " {\n"
+	for _, sig := range signatures {
+		args := []string{}
+		for _, arg := range sig.Args {
+			args = append(args, arg.Name+": "+arg.Type)
+		}
+		sdl += "  " + sig.Name + "(" + strings.Join(args, ", ") + "): " + sig.Returns + "\n"
+	}
+	return sdl + "}"
+}
+
+// isScalar reports whether the GraphQL type is a custom scalar rather than
+// one of the built-in ones.
+func isScalar(typename string) bool {
+	switch typename {
+	case "String", "Int", "Float", "Boolean", "ID":
+		return false
+	}
+	return true
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := []string{}
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,117 @@
+package trpc
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+	"github.com/tk04/genql/zod"
+)
+
+// Init is the module the routers create their procedures with. The context
+// passes the Prisma client, and prismaError maps Prisma errors to tRPC errors.
+// superjson carries the Date, BigInt and Buffer values of the Zod inputs and
+// the Prisma records over JSON.
+const Init = `import { PrismaClient, Prisma } from "@prisma/client";
+import { initTRPC, TRPCError } from "@trpc/server";
+import superjson from "superjson";
+
+export interface Context {
+	prisma: PrismaClient;
+}
+
+// Bytes fields are sent as base64
+superjson.registerCustom<Buffer, string>(
+	{
+		isApplicable: (value): value is Buffer => Buffer.isBuffer(value),
+		serialize: (value) => value.toString("base64"),
+		deserialize: (value) => Buffer.from(value, "base64"),
+	},
+	"buffer"
+);
+
+const t = initTRPC.context<Context>().create({ transformer: superjson });
+
+export const router = t.router;
+export const publicProcedure = t.procedure;
+
+export const prismaError = (model: string) => (error: unknown): never => {
+	if (error instanceof Prisma.PrismaClientKnownRequestError) {
+		switch (error.code) {
+			case "P2025":
+				throw new TRPCError({ code: "NOT_FOUND", message: model + " not found", cause: error });
+			case "P2002":
+			case "P2003":
+				throw new TRPCError({ code: "CONFLICT", message: error.message, cause: error });
+		}
+	}
+	throw error;
+};
+`
+
+// RouterName returns the name of the model's router, e.g. userRouter.
+func RouterName(modelName string) string {
+	return strings.ToLower(modelName) + "Router"
+}
+
+// Router returns a tRPC router with get, list, create, update and delete
+// procedures for the model, validating their input with Zod. Like the REST
+// routes, they only return the fields of the object type.
+func Router(m prismaUtil.Model, schema prismaUtil.Schema) string {
+	delegate := "ctx.prisma." + strings.ToLower(m.Name)
+	idField, _ := m.IdField()
+	catch := ".catch(prismaError(\"" + m.Name + "\"))"
+	where := "{ id: input.id }"
+	listWhere := ""
+	if m.SoftDelete() {
+		where = "{ id: input.id, " + prismaUtil.SoftDeleteField + ": null }"
+		listWhere = "where: { " + prismaUtil.SoftDeleteField + ": null }, "
+	}
+	idInput := "z.object({ id: " + zod.FieldSchema(idField, prismaUtil.UpdateMode, schema) + " })"
+	procedure := func(name string, input string, kind string, body string) string {
+		return "\t" + name + ": publicProcedure\n\t\t.input(" + input + ")\n\t\t." + kind + "(" + body + "),\n"
+	}
+
+	ts := "import { z } from \"zod\";\nimport { router, publicProcedure, prismaError } from \"../trpc\";\n\n"
+	ts += "const createInput = " + zod.Object(m, prismaUtil.CreateMode, schema) + ";\n\n"
+	ts += "const updateInput = " + zod.Object(m, prismaUtil.UpdateMode, schema) + ";\n\n"
+	ts += "const select = " + selection(m, schema) + ";\n\n"
+	ts += "export const " + RouterName(m.Name) + " = router({\n"
+	ts += procedure("get", idInput, "query", "({ ctx, input }) =>\n\t\t\t"+delegate+".findFirst({ where: "+where+", select })"+catch)
+	ts += procedure("list", "z.object({ take: z.number().int().min(1).max(100).default(20), skip: z.number().int().min(0).default(0) }).default({})", "query",
+		"({ ctx, input }) =>\n\t\t\t"+delegate+".findMany({ "+listWhere+"take: input.take, skip: input.skip, orderBy: { id: \"asc\" }, select })"+catch)
+	ts += procedure("create", "createInput", "mutation", "({ ctx, input }) =>\n\t\t\t"+delegate+".create({ data: input, select })"+catch)
+	ts += procedure("update", "updateInput", "mutation", "({ ctx, input }) => {\n\t\t\tconst { id, ...data } = input;\n\t\t\treturn "+delegate+".update({ where: "+strings.Replace(where, "id: input.id", "id", 1)+", data, select })"+catch+";\n\t\t}")
+	if m.SoftDelete() {
+		ts += procedure("delete", idInput, "mutation", "({ ctx, input }) =>\n\t\t\t"+delegate+".update({ where: "+where+", data: { "+prismaUtil.SoftDeleteField+": new Date() }, select })"+catch)
+	} else {
+		ts += procedure("delete", idInput, "mutation", "({ ctx, input }) =>\n\t\t\t"+delegate+".delete({ where: "+where+", select })"+catch)
+	}
+	ts += "});\n"
+	return ts
+}
+
+// selection returns the Prisma select of the fields of the model's object
+// type, leaving out relations and hidden and writeonly fields.
+func selection(m prismaUtil.Model, schema prismaUtil.Schema) string {
+	entries := []string{}
+	for _, f := range schema.WithEnums(m).FieldsIn(prismaUtil.ObjectMode) {
+		entries = append(entries, f.Name+": true")
+	}
+	return "{ " + strings.Join(entries, ", ") + " }"
+}
+
+// AppRouter returns the root router merging the routers of the given models.
+func AppRouter(modelNames []string) string {
+	sort.Strings(modelNames)
+	ts := "import { router } from \"../trpc\";\n"
+	for _, name := range modelNames {
+		ts += "import { " + RouterName(name) + " } from \"./" + name + "\";\n"
+	}
+	ts += "\nexport const appRouter = router({\n"
+	for _, name := range modelNames {
+		ts += "\t" + strings.ToLower(name) + ": " + RouterName(name) + ",\n"
+	}
+	ts += "});\n\nexport type AppRouter = typeof appRouter;\n"
+	return ts
+}
@@ -0,0 +1,97 @@
+package zod
+
+import (
+	"strings"
+
+	"github.com/tk04/genql/prismaUtil"
+)
+
+var MAPPED_ZOD = map[prismaUtil.PrismaType]string{
+	prismaUtil.FloatType:    "z.number()",
+	prismaUtil.IntType:      "z.number().int()",
+	prismaUtil.BytesType:    "z.instanceof(Buffer)",
+	prismaUtil.JsonType:     "z.any()",
+	prismaUtil.BooleanType:  "z.boolean()",
+	prismaUtil.StringType:   "z.string()",
+	prismaUtil.BigIntType:   "z.bigint()",
+	prismaUtil.DateTimeType: "z.date()",
+}
+
+// Schemas returns the Zod schemas validating the model's create and update
+// inputs, with the same fields as the input classes of the resolvers.
+func Schemas(m prismaUtil.Model, schema prismaUtil.Schema) string {
+	ts := "import { z } from \"zod\";\n\n"
+	ts += "export const create" + m.Name + "Schema = " + Object(m, prismaUtil.CreateMode, schema) + ";\n\n"
+	ts += "export const update" + m.Name + "Schema = " + Object(m, prismaUtil.UpdateMode, schema) + ";\n"
+	return ts
+}
+
+// Object returns the z.object of the fields the input generated for mode has.
+func Object(m prismaUtil.Model, mode prismaUtil.TypeMode, schema prismaUtil.Schema) string {
+	entries := []string{}
+	for _, f := range m.Fields {
+		// relations aren't validated, enums are
+		if f.Typename == prismaUtil.NPType && !schema.IsEnum(f.NPType) {
+			continue
+		}
+		if !f.InMode(mode) {
+			continue
+		}
+		entries = append(entries, "\t"+f.Name+": "+FieldSchema(f, mode, schema)+",")
+	}
+	return "z.object({\n" + strings.Join(entries, "\n") + "\n})"
+}
+
+// FieldSchema returns the Zod schema of a field. Optional columns accept
+// null, and fields that may be left out of the input are optional.
+func FieldSchema(f prismaUtil.Field, mode prismaUtil.TypeMode, schema prismaUtil.Schema) string {
+	zod := MAPPED_ZOD[f.Typename]
+	if e, ok := schema.Enum(f.NPType); ok && f.Typename == prismaUtil.NPType {
+		values := []string{}
+		for _, v := range e.Values {
+			values = append(values, "\""+v.Name+"\"")
+		}
+		zod = "z.enum([" + strings.Join(values, ", ") + "])"
+	}
+	zod += checks(f)
+	if f.IsArray {
+		zod = "z.array(" + zod + ")"
+	}
+	if f.IsOptional {
+		zod += ".nullable()"
+	}
+	if f.Nullable(mode) {
+		zod += ".optional()"
+	}
+	return zod
+}
+
+// checks returns the refinements of the field's validation annotations.
+func checks(f prismaUtil.Field) string {
+	zod := ""
+	isString := f.Typename == prismaUtil.StringType
+	suffix := ""
+	if f.Typename == prismaUtil.BigIntType {
+		suffix = "n" // bigint literal
+	}
+	if _, ok := f.Annotation("email"); ok && isString {
+		zod += ".email()"
+	}
+	if _, ok := f.Annotation("url"); ok && isString {
+		zod += ".url()"
+	}
+	if _, ok := f.Annotation("uuid"); (ok || strings.Contains(f.Attribute, "@default(uuid())")) && isString {
+		zod += ".uuid()"
+	}
+	if min, ok := f.Limit("min"); ok {
+		zod += ".min(" + min + suffix + ")"
+	}
+	max, ok := f.Limit("max")
+	if length, native := f.NativeLength(); !ok && native {
+		max, ok = length, true
+	}
+	if ok {
+		zod += ".max(" + max + suffix + ")"
+	}
+	return zod
+}